@@ -15,6 +15,7 @@ func main() {
 	var postgreSQLConfigPath string
 
 	const databaseURLEnv = "DATABASE_URL"
+	const secretsKeyEnv = "SECRETS_KEY"
 
 	flag.StringVar(&gRPCConfigPath, "grpc_conf", "configs/grpc_server_config.toml", "path to gRPC server config file")
 	flag.StringVar(&postgreSQLConfigPath, "postgresql_conf", "configs/postgresql_config.toml", "path to PostgreSQL config file")
@@ -37,6 +38,15 @@ func main() {
 	if _, err := toml.DecodeFile(gRPCConfigPath, &configGRPCServer); err != nil {
 		log.Fatal(err)
 	}
+	if secretsKey, ok := os.LookupEnv(secretsKeyEnv); ok {
+		configGRPCServer.SecretsKey = secretsKey
+	}
+	if err := app.StartExpiryJob(configGRPCServer); err != nil {
+		log.Fatal(err)
+	}
+	if err := app.StartIdempotencyPurgeJob(configGRPCServer); err != nil {
+		log.Fatal(err)
+	}
 	if err := app.StartGRPCServer(configGRPCServer); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/server"
+	"log"
+	"time"
+)
+
+// StartExpiryJob periodically creates a new version of every config whose
+// latest version has expired keys.
+func StartExpiryJob(config *server.Config) error {
+	interval, err := time.ParseDuration(config.ExpiryCheckInterval)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := expireKeys(time.Now()); err != nil {
+				log.Println(err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func expireKeys(now time.Time) error {
+	screp := database.Psql.ServiceConfig()
+
+	configs, err := screp.ReadExpiring("")
+	if err != nil {
+		return err
+	}
+
+	for _, sc := range configs {
+		if len(sc.ExpiringKeys(now)) == 0 {
+			continue
+		}
+
+		expired, err := screp.Expire(&models.ServiceConfig{Service: sc.Service}, now)
+		if err != nil {
+			log.Println(err)
+		} else if expired != nil {
+			log.Printf("keys %v of service '%s' expired in version %d", expired.Spec.Expired, expired.Service, expired.Version)
+		}
+	}
+
+	return nil
+}
@@ -6,7 +6,10 @@ import (
 )
 
 func StartGRPCServer(config *server.Config) error {
-	s := server.NewGRPCServer()
+	s, err := server.NewGRPCServer(config)
+	if err != nil {
+		return err
+	}
 
 	l, err := net.Listen(config.Network, config.BindAddr)
 	if err != nil {
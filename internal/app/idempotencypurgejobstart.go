@@ -0,0 +1,35 @@
+package app
+
+import (
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/server"
+	"log"
+	"time"
+)
+
+// StartIdempotencyPurgeJob periodically removes the idempotency keys older
+// than their retention.
+func StartIdempotencyPurgeJob(config *server.Config) error {
+	interval, err := time.ParseDuration(config.IdempotencyPurgeInterval)
+	if err != nil {
+		return err
+	}
+
+	retention, err := time.ParseDuration(config.IdempotencyRetention)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := database.Psql.Idempotency().Purge(retention); err != nil {
+				log.Println(err)
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"encoding/json"
+	"github.com/lib/pq"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"log"
+	"time"
+)
+
+const changesChannel = "config_changes"
+
+// ListenChanges delivers the changes of service configs made through any
+// controller instance. A nil change is delivered after the connection to the
+// database was lost, since changes may have been missed meanwhile.
+func (p *PostgreSQL) ListenChanges(changes chan<- *models.ConfigChange) error {
+	listener := pq.NewListener(p.config.DatabaseURL, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Println(err)
+		}
+	})
+
+	if err := listener.Listen(changesChannel); err != nil {
+		listener.Close()
+		return err
+	}
+
+	go func() {
+		for n := range listener.Notify {
+			if n == nil {
+				changes <- nil
+				continue
+			}
+
+			change := &models.ConfigChange{}
+			if err := json.Unmarshal([]byte(n.Extra), change); err != nil {
+				log.Println(err)
+				continue
+			}
+			changes <- change
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/models"
+)
+
+type ChangesetRepository struct {
+	psql *PostgreSQL
+}
+
+func getUnknownOperationError(kind string) string {
+	return fmt.Sprintf("unknown operation '%s'", kind)
+}
+
+// OperationError is the error of the operation of a changeset at Index that
+// caused the changeset to be rolled back.
+type OperationError struct {
+	Index int
+	Err   error
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Apply applies the operations of the changeset in one transaction and
+// records the changeset ID on every version created. prepare, if set, is called
+// before each operation is applied, in a request transaction it sees the
+// operations applied before. It returns an *OperationError if prepare or an
+// operation fails.
+func (r *ChangesetRepository) Apply(cs *models.Changeset, prepare func(i int, op *models.ChangesetOperation) error) (*models.Changeset, error) {
+	tx, err := r.psql.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow("INSERT INTO changesets (message) VALUES ($1) RETURNING id",
+		cs.Message,
+	).Scan(&cs.ID); err != nil {
+		return nil, err
+	}
+
+	for i, op := range cs.Operations {
+		if prepare != nil {
+			if err = prepare(i, op); err != nil {
+				return nil, &OperationError{Index: i, Err: err}
+			}
+		}
+		if err = r.apply(tx, cs.ID, op); err != nil {
+			return nil, &OperationError{Index: i, Err: err}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return cs, nil
+}
+
+func (r *ChangesetRepository) apply(tx Tx, changesetID int, op *models.ChangesetOperation) error {
+	screp := r.psql.ServiceConfig()
+	c := op.Config
+
+	switch op.Kind {
+	case models.OperationCreate:
+		if err := screp.create(tx, c); err != nil {
+			return err
+		}
+		c.Version = 1
+
+		if row := tx.QueryRow("UPDATE data_configs SET changeset_id=$1 WHERE (config_id=$2) AND (version=$3)",
+			changesetID,
+			c.ID,
+			c.Version,
+		); row.Err() != nil {
+			return row.Err()
+		}
+	case models.OperationUpdate:
+		return screp.updateFrom(tx, c, op.BaseVersion, changesetID)
+	case models.OperationDelete:
+		if err := tx.QueryRow("SELECT id FROM configs WHERE service=$1 FOR UPDATE",
+			c.Service,
+		).Scan(&c.ID); err == sql.ErrNoRows {
+			return &NotFoundError{Resource: ResourceConfig, Name: c.Service, Msg: getConfigForServiceNotFoundError(c.Service)}
+		} else if err != nil {
+			return err
+		}
+
+		if op.BaseVersion != 0 {
+			if err := tx.QueryRow("SELECT version FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1",
+				c.ID,
+			).Scan(&c.Version); err != nil {
+				return err
+			} else if c.Version != op.BaseVersion {
+				return &PreconditionError{Type: PreconditionVersionConflict, Subject: c.Service, CurrentVersion: c.Version, Msg: getConfigVersionConflictError(c.Service, c.Version, op.BaseVersion)}
+			}
+		}
+
+		return screp.deleteAll(tx, c)
+	default:
+		return &InvalidArgumentError{Field: "operation", Msg: getUnknownOperationError(op.Kind)}
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package database
+
+import (
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"regexp"
+	"testing"
+)
+
+func TestChangesetApply(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &ChangesetRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	type mockBehavior func(cs *models.Changeset)
+
+	specData := []byte(`{}`)
+
+	expectChangeset := func(cs *models.Changeset) {
+		rows := mock.NewRows([]string{"id"}).AddRow(7)
+		query := regexp.QuoteMeta("INSERT INTO changesets (message) VALUES ($1) RETURNING id")
+		mock.ExpectQuery(query).
+			WithArgs(cs.Message).WillReturnRows(rows)
+	}
+	expectCreate := func(serviceName string) {
+		rows := mock.NewRows([]string{"exist"}).AddRow(false)
+		query := regexp.QuoteMeta("SELECT EXISTS(SELECT service FROM configs WHERE service=$1)")
+		mock.ExpectQuery(query).
+			WithArgs(serviceName).WillReturnRows(rows)
+
+		rows = mock.NewRows([]string{"id"}).AddRow(2)
+		query = regexp.QuoteMeta("INSERT INTO configs (service) VALUES ($1) RETURNING id")
+		mock.ExpectQuery(query).
+			WithArgs(serviceName).WillReturnRows(rows)
+
+		query = regexp.QuoteMeta("INSERT INTO data_configs (config_id, version, data, spec) VALUES ($1, $2, $3, $4)")
+		mock.ExpectQuery(query).
+			WithArgs(2, 1, []byte(`{"url":"http://new"}`), specData).WillReturnRows(&sqlmock.Rows{})
+
+		query = regexp.QuoteMeta("UPDATE data_configs SET changeset_id=$1 WHERE (config_id=$2) AND (version=$3)")
+		mock.ExpectQuery(query).
+			WithArgs(7, 2, 1).WillReturnRows(&sqlmock.Rows{})
+	}
+	expectLatest := func(serviceName string, version int) {
+		rows := mock.NewRows([]string{"id"}).AddRow(1)
+		query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1 FOR UPDATE")
+		mock.ExpectQuery(query).
+			WithArgs(serviceName).WillReturnRows(rows)
+
+		rows = mock.NewRows([]string{"version", "data", "spec"}).AddRow(version, []byte(`{"url":"http://old"}`), specData)
+		query = regexp.QuoteMeta("SELECT version, data, spec FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
+		mock.ExpectQuery(query).
+			WithArgs(1).WillReturnRows(rows)
+	}
+
+	testTable := []struct {
+		name           string
+		mockBehavior   mockBehavior
+		changeset      *models.Changeset
+		prepare        func(i int, op *models.ChangesetOperation) error
+		expectVersions []uint32
+		wantError      bool
+		wantIndex      int
+	}{
+		{
+			name: "OK",
+			changeset: &models.Changeset{
+				Message: "rotate endpoint",
+				Operations: []*models.ChangesetOperation{
+					{
+						Kind:        models.OperationUpdate,
+						Config:      &models.ServiceConfig{Service: "gateway", Data: map[string]string{"url": "http://new"}, Message: "rotate endpoint"},
+						BaseVersion: 3,
+					},
+					{
+						Kind:   models.OperationCreate,
+						Config: &models.ServiceConfig{Service: "backend", Data: map[string]string{"url": "http://new"}},
+					},
+				},
+			},
+			expectVersions: []uint32{4, 1},
+			mockBehavior: func(cs *models.Changeset) {
+				mock.ExpectBegin()
+				expectChangeset(cs)
+
+				expectLatest("gateway", 3)
+				query := regexp.QuoteMeta("INSERT INTO data_configs (config_id, version, data, spec, message, changeset_id) VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0))")
+				mock.ExpectQuery(query).
+					WithArgs(1, 4, []byte(`{"url":"http://new"}`), specData, cs.Message, 7).WillReturnRows(&sqlmock.Rows{})
+
+				expectCreate("backend")
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK Delete",
+			changeset: &models.Changeset{
+				Operations: []*models.ChangesetOperation{
+					{
+						Kind:        models.OperationDelete,
+						Config:      &models.ServiceConfig{Service: "legacy"},
+						BaseVersion: 2,
+					},
+				},
+			},
+			expectVersions: []uint32{2},
+			mockBehavior: func(cs *models.Changeset) {
+				mock.ExpectBegin()
+				expectChangeset(cs)
+
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1 FOR UPDATE")
+				mock.ExpectQuery(query).
+					WithArgs("legacy").WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"version"}).AddRow(2)
+				query = regexp.QuoteMeta("SELECT version FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
+				mock.ExpectQuery(query).
+					WithArgs(1).WillReturnRows(rows)
+
+				query = regexp.QuoteMeta("DELETE FROM data_configs WHERE config_id=$1")
+				mock.ExpectQuery(query).
+					WithArgs(1).WillReturnRows(&sqlmock.Rows{})
+
+				query = regexp.QuoteMeta("DELETE FROM configs WHERE id=$1")
+				mock.ExpectQuery(query).
+					WithArgs(1).WillReturnRows(&sqlmock.Rows{})
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ConfigVersionConflict",
+			changeset: &models.Changeset{
+				Operations: []*models.ChangesetOperation{
+					{
+						Kind:   models.OperationCreate,
+						Config: &models.ServiceConfig{Service: "backend", Data: map[string]string{"url": "http://new"}},
+					},
+					{
+						Kind:        models.OperationUpdate,
+						Config:      &models.ServiceConfig{Service: "gateway", Data: map[string]string{"url": "http://new"}},
+						BaseVersion: 2,
+					},
+				},
+			},
+			wantError: true,
+			wantIndex: 1,
+			mockBehavior: func(cs *models.Changeset) {
+				mock.ExpectBegin()
+				expectChangeset(cs)
+				expectCreate("backend")
+				expectLatest("gateway", 3)
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "PrepareFailed",
+			changeset: &models.Changeset{
+				Operations: []*models.ChangesetOperation{
+					{
+						Kind:   models.OperationCreate,
+						Config: &models.ServiceConfig{Service: "backend", Data: map[string]string{"url": "http://new"}},
+					},
+					{
+						Kind:   models.OperationCreate,
+						Config: &models.ServiceConfig{Service: "frontend", Data: map[string]string{"url": "http://new"}},
+					},
+				},
+			},
+			prepare: func(i int, op *models.ChangesetOperation) error {
+				if op.Config.Service == "frontend" {
+					return &InvalidArgumentError{Field: "confData", Msg: "quota exceeded"}
+				}
+				return nil
+			},
+			wantError: true,
+			wantIndex: 1,
+			mockBehavior: func(cs *models.Changeset) {
+				mock.ExpectBegin()
+				expectChangeset(cs)
+				expectCreate("backend")
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.changeset)
+
+			got, err := r.Apply(testCase.changeset, testCase.prepare)
+			if testCase.wantError {
+				var opErr *OperationError
+				if assert.ErrorAs(t, err, &opErr) {
+					assert.Equal(t, testCase.wantIndex, opErr.Index)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 7, got.ID)
+				for i, op := range got.Operations {
+					assert.Equal(t, testCase.expectVersions[i], op.Config.Version)
+				}
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/wphylici/contest-cloud/internal/models"
 	"reflect"
+	"time"
 )
 
 type ServiceConfigRepository struct {
@@ -28,75 +29,94 @@ func getNoChangeInConfigError() string {
 	return fmt.Sprintf("no change in config")
 }
 
+func getConfigVersionConflictError(serviceName string, version uint32, baseVersion uint32) string {
+	return fmt.Sprintf("config of service '%s' is at version '%d', expected version '%d'", serviceName, version, baseVersion)
+}
+
 func (r *ServiceConfigRepository) Create(c *models.ServiceConfig) (*models.ServiceConfig, error) {
-	var isServiceExist bool
-	var tx *sql.Tx
+	var tx Tx
 
-	tx, err := r.psql.db.Begin()
+	tx, err := r.psql.begin()
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	if err = r.psql.db.QueryRow("SELECT EXISTS(SELECT service FROM configs WHERE service=$1)",
+	if err = r.create(tx, c); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (r *ServiceConfigRepository) create(tx Tx, c *models.ServiceConfig) error {
+	var isServiceExist bool
+
+	if err := tx.QueryRow("SELECT EXISTS(SELECT service FROM configs WHERE service=$1)",
 		c.Service,
 	).Scan(&isServiceExist); err != nil {
-		return nil, err
+		return err
 	} else if !isServiceExist {
 		if err = tx.QueryRow(
 			"INSERT INTO configs (service) VALUES ($1) RETURNING id",
 			c.Service,
 		).Scan(&c.ID); err != nil {
-			return nil, err
+			return err
 		}
 	} else {
-		return nil, fmt.Errorf(getConfigAlreadyBeenCreatedError(c.Service))
+		return &AlreadyExistsError{Resource: ResourceConfig, Name: c.Service, Msg: getConfigAlreadyBeenCreatedError(c.Service)}
 	}
 
 	configData, err := json.Marshal(c.Data)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	specData, err := json.Marshal(c.Spec)
+	if err != nil {
+		return err
 	}
 
 	if row := tx.QueryRow(
-		"INSERT INTO data_configs (config_id, version, data) VALUES ($1, $2, $3)",
+		"INSERT INTO data_configs (config_id, version, data, spec) VALUES ($1, $2, $3, $4)",
 		c.ID,
 		1,
 		configData,
+		specData,
 	); row.Err() != nil {
-		return nil, row.Err()
-	}
-
-	if err = tx.Commit(); err != nil {
-		return nil, err
+		return row.Err()
 	}
 
-	return c, nil
+	return nil
 }
 
 func (r *ServiceConfigRepository) Read(c *models.ServiceConfig) (*models.ServiceConfig, error) {
 
-	if err := r.psql.db.QueryRow("SELECT id FROM configs WHERE service=$1",
+	if err := r.psql.conn().QueryRow("SELECT id FROM configs WHERE service=$1",
 		c.Service,
 	).Scan(&c.ID); err == sql.ErrNoRows {
-		return nil, fmt.Errorf(getConfigForServiceNotFoundError(c.Service))
+		return nil, &NotFoundError{Resource: ResourceConfig, Name: c.Service, Msg: getConfigForServiceNotFoundError(c.Service)}
 	} else if err != nil {
 		return nil, err
 	}
 
-	var configData []byte
+	var configData, specData []byte
 	if c.Version == 0 {
-		if err := r.psql.db.QueryRow("SELECT data, version FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1",
+		if err := r.psql.conn().QueryRow("SELECT data, spec, version FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1",
 			c.ID,
-		).Scan(&configData, &c.Version); err != nil {
+		).Scan(&configData, &specData, &c.Version); err != nil {
 			return nil, err
 		}
 	} else {
-		if err := r.psql.db.QueryRow("SELECT data FROM data_configs WHERE (config_id=$1) AND (version=$2)",
+		if err := r.psql.conn().QueryRow("SELECT data, spec FROM data_configs WHERE (config_id=$1) AND (version=$2)",
 			c.ID,
 			c.Version,
-		).Scan(&configData); err == sql.ErrNoRows {
-			return nil, fmt.Errorf(getConfigVersionNotFoundError(c.Service, c.Version))
+		).Scan(&configData, &specData); err == sql.ErrNoRows {
+			return nil, &NotFoundError{Resource: ResourceConfigVersion, Name: c.Service, Msg: getConfigVersionNotFoundError(c.Service, c.Version)}
 		} else if err != nil {
 			return nil, err
 		}
@@ -106,30 +126,34 @@ func (r *ServiceConfigRepository) Read(c *models.ServiceConfig) (*models.Service
 		return nil, err
 	}
 
+	if err := json.Unmarshal(specData, &c.Spec); err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
 func (r *ServiceConfigRepository) Update(c *models.ServiceConfig) (*models.ServiceConfig, error) {
 	var isServiceExist bool
 
-	if err := r.psql.db.QueryRow("SELECT EXISTS(SELECT service FROM configs WHERE service=$1)",
+	if err := r.psql.conn().QueryRow("SELECT EXISTS(SELECT service FROM configs WHERE service=$1)",
 		c.Service,
 	).Scan(&isServiceExist); err != nil {
 		return nil, err
 	} else if !isServiceExist {
-		return nil, fmt.Errorf(getConfigForServiceNotFoundError(c.Service))
+		return nil, &NotFoundError{Resource: ResourceConfig, Name: c.Service, Msg: getConfigForServiceNotFoundError(c.Service)}
 	} else {
-		if err = r.psql.db.QueryRow("SELECT id FROM configs WHERE service=$1",
+		if err = r.psql.conn().QueryRow("SELECT id FROM configs WHERE service=$1",
 			c.Service,
 		).Scan(&c.ID); err != nil {
 			return nil, err
 		}
 	}
 
-	var lastConfigData []byte
-	if err := r.psql.db.QueryRow("SELECT version, data FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1",
+	var lastConfigData, lastSpecData []byte
+	if err := r.psql.conn().QueryRow("SELECT version, data, spec FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1",
 		c.ID,
-	).Scan(&c.Version, &lastConfigData); err != nil {
+	).Scan(&c.Version, &lastConfigData, &lastSpecData); err != nil {
 		return nil, err
 	}
 	c.Version++
@@ -139,62 +163,130 @@ func (r *ServiceConfigRepository) Update(c *models.ServiceConfig) (*models.Servi
 		return nil, err
 	}
 
-	if !reflect.DeepEqual(lastConfigData, configData) {
-		if row := r.psql.db.QueryRow(
-			"INSERT INTO data_configs (config_id, version, data) VALUES ($1, $2, $3)",
+	specData, err := json.Marshal(c.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reflect.DeepEqual(lastConfigData, configData) || !reflect.DeepEqual(lastSpecData, specData) {
+		if row := r.psql.conn().QueryRow(
+			"INSERT INTO data_configs (config_id, version, data, spec) VALUES ($1, $2, $3, $4)",
 			c.ID,
 			c.Version,
 			configData,
+			specData,
 		); row.Err() != nil {
 			return nil, row.Err()
 		}
 	} else {
-		return nil, fmt.Errorf(getNoChangeInConfigError())
+		return nil, &PreconditionError{Type: PreconditionNoChange, Subject: c.Service, Msg: getNoChangeInConfigError()}
 	}
 
 	return c, nil
 }
 
+// UpdateFrom stores the service config and its message as the version
+// following baseVersion. It fails if another version of the service config was
+// stored since.
+func (r *ServiceConfigRepository) UpdateFrom(c *models.ServiceConfig, baseVersion uint32) (*models.ServiceConfig, error) {
+	tx, err := r.psql.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = r.updateFrom(tx, c, baseVersion, 0); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (r *ServiceConfigRepository) updateFrom(tx Tx, c *models.ServiceConfig, baseVersion uint32, changesetID int) error {
+	if err := tx.QueryRow("SELECT id FROM configs WHERE service=$1 FOR UPDATE",
+		c.Service,
+	).Scan(&c.ID); err == sql.ErrNoRows {
+		return &NotFoundError{Resource: ResourceConfig, Name: c.Service, Msg: getConfigForServiceNotFoundError(c.Service)}
+	} else if err != nil {
+		return err
+	}
+
+	var lastConfigData, lastSpecData []byte
+	if err := tx.QueryRow("SELECT version, data, spec FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1",
+		c.ID,
+	).Scan(&c.Version, &lastConfigData, &lastSpecData); err != nil {
+		return err
+	}
+
+	if c.Version != baseVersion {
+		return &PreconditionError{Type: PreconditionVersionConflict, Subject: c.Service, CurrentVersion: c.Version, Msg: getConfigVersionConflictError(c.Service, c.Version, baseVersion)}
+	}
+	c.Version++
+
+	configData, err := json.Marshal(c.Data)
+	if err != nil {
+		return err
+	}
+
+	specData, err := json.Marshal(c.Spec)
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(lastConfigData, configData) && reflect.DeepEqual(lastSpecData, specData) {
+		return &PreconditionError{Type: PreconditionNoChange, Subject: c.Service, Msg: getNoChangeInConfigError()}
+	}
+
+	if row := tx.QueryRow(
+		"INSERT INTO data_configs (config_id, version, data, spec, message, changeset_id) VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0))",
+		c.ID,
+		c.Version,
+		configData,
+		specData,
+		c.Message,
+		changesetID,
+	); row.Err() != nil {
+		return row.Err()
+	}
+
+	return nil
+}
+
 func (r *ServiceConfigRepository) Delete(c *models.ServiceConfig) (*models.ServiceConfig, error) {
 
-	if err := r.psql.db.QueryRow("SELECT id FROM configs WHERE service=$1",
+	if err := r.psql.conn().QueryRow("SELECT id FROM configs WHERE service=$1",
 		c.Service,
 	).Scan(&c.ID); err == sql.ErrNoRows {
-		return nil, fmt.Errorf(getConfigForServiceNotFoundError(c.Service))
+		return nil, &NotFoundError{Resource: ResourceConfig, Name: c.Service, Msg: getConfigForServiceNotFoundError(c.Service)}
 	} else if err != nil {
 		return nil, err
 	}
 
 	if c.Version == 0 {
 
-		tx, err := r.psql.db.Begin()
+		tx, err := r.psql.begin()
 		if err != nil {
 			return nil, err
 		}
 
-		if row := tx.QueryRow("DELETE FROM data_configs WHERE config_id=$1",
-			c.ID,
-		); row.Err() != nil {
-			tx.Rollback()
-			return nil, row.Err()
-		}
-
-		if row := tx.QueryRow("DELETE FROM configs WHERE id=$1",
-			c.ID,
-		); row.Err() != nil {
+		if err = r.deleteAll(tx, c); err != nil {
 			tx.Rollback()
-			return nil, row.Err()
+			return nil, err
 		}
 
 		if err = tx.Commit(); err != nil {
 			return nil, err
 		}
 	} else {
-		if err := r.psql.db.QueryRow("DELETE FROM data_configs WHERE (config_id=$1) AND (version=$2) RETURNING config_id",
+		if err := r.psql.conn().QueryRow("DELETE FROM data_configs WHERE (config_id=$1) AND (version=$2) RETURNING config_id",
 			c.ID,
 			c.Version,
 		).Scan(&c.ID); err == sql.ErrNoRows {
-			return nil, fmt.Errorf(getConfigVersionNotFoundError(c.Service, c.Version))
+			return nil, &NotFoundError{Resource: ResourceConfigVersion, Name: c.Service, Msg: getConfigVersionNotFoundError(c.Service, c.Version)}
 		} else if err != nil {
 			return nil, err
 		}
@@ -202,3 +294,303 @@ func (r *ServiceConfigRepository) Delete(c *models.ServiceConfig) (*models.Servi
 
 	return c, nil
 }
+
+func (r *ServiceConfigRepository) deleteAll(tx Tx, c *models.ServiceConfig) error {
+	if row := tx.QueryRow("DELETE FROM data_configs WHERE config_id=$1",
+		c.ID,
+	); row.Err() != nil {
+		return row.Err()
+	}
+
+	if row := tx.QueryRow("DELETE FROM configs WHERE id=$1",
+		c.ID,
+	); row.Err() != nil {
+		return row.Err()
+	}
+
+	return nil
+}
+
+// ReadAllVersions returns every stored version of the service config in
+// ascending order, the list is empty if the service has no config.
+func (r *ServiceConfigRepository) ReadAllVersions(c *models.ServiceConfig) ([]*models.ServiceConfig, error) {
+
+	if err := r.psql.conn().QueryRow("SELECT id FROM configs WHERE service=$1",
+		c.Service,
+	).Scan(&c.ID); err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.psql.conn().Query("SELECT version, data, spec FROM data_configs WHERE config_id=$1 ORDER BY version",
+		c.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanVersions(rows, c)
+}
+
+// ReadVersionsAfter returns the stored versions of the service config newer
+// than afterVersion in ascending order, the list is empty if the service has
+// no config.
+func (r *ServiceConfigRepository) ReadVersionsAfter(c *models.ServiceConfig, afterVersion uint32) ([]*models.ServiceConfig, error) {
+
+	if err := r.psql.conn().QueryRow("SELECT id FROM configs WHERE service=$1",
+		c.Service,
+	).Scan(&c.ID); err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.psql.conn().Query("SELECT version, data, spec FROM data_configs WHERE (config_id=$1) AND (version>$2) ORDER BY version",
+		c.ID,
+		afterVersion,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanVersions(rows, c)
+}
+
+func scanVersions(rows *sql.Rows, c *models.ServiceConfig) ([]*models.ServiceConfig, error) {
+	defer rows.Close()
+
+	var configs []*models.ServiceConfig
+	for rows.Next() {
+		var configData, specData []byte
+		sc := &models.ServiceConfig{
+			ID:      c.ID,
+			Service: c.Service,
+		}
+		if err := rows.Scan(&sc.Version, &configData, &specData); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(configData, &sc.Data); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(specData, &sc.Spec); err != nil {
+			return nil, err
+		}
+		configs = append(configs, sc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+// ReadExpiring returns the latest config version of every service, or only of
+// the given service, that has expiring keys.
+func (r *ServiceConfigRepository) ReadExpiring(serviceName string) ([]*models.ServiceConfig, error) {
+
+	rows, err := r.psql.conn().Query(
+		"SELECT c.id, c.service, d.version, d.data, d.spec FROM configs c JOIN data_configs d ON d.config_id=c.id "+
+			"WHERE ($1='' OR c.service=$1) AND (d.spec::jsonb ? 'expires') "+
+			"AND d.version=(SELECT MAX(version) FROM data_configs WHERE config_id=c.id) ORDER BY c.service",
+		serviceName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var configs []*models.ServiceConfig
+	for rows.Next() {
+		var configData, specData []byte
+		sc := &models.ServiceConfig{}
+		if err = rows.Scan(&sc.ID, &sc.Service, &sc.Version, &configData, &specData); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(configData, &sc.Data); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(specData, &sc.Spec); err != nil {
+			return nil, err
+		}
+		configs = append(configs, sc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+// Expire creates a new version of the service config in which the keys expired
+// at now are reverted to their prior value. It returns nil if no key of the
+// latest version has expired.
+func (r *ServiceConfigRepository) Expire(c *models.ServiceConfig, now time.Time) (*models.ServiceConfig, error) {
+	tx, err := r.psql.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow("SELECT id FROM configs WHERE service=$1 FOR UPDATE",
+		c.Service,
+	).Scan(&c.ID); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceConfig, Name: c.Service, Msg: getConfigForServiceNotFoundError(c.Service)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	var configData, specData []byte
+	if err = tx.QueryRow("SELECT version, data, spec FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1",
+		c.ID,
+	).Scan(&c.Version, &configData, &specData); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(configData, &c.Data); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(specData, &c.Spec); err != nil {
+		return nil, err
+	}
+
+	if expired := c.ExpireKeys(now); len(expired) == 0 {
+		return nil, nil
+	}
+	c.Version++
+
+	if configData, err = json.Marshal(c.Data); err != nil {
+		return nil, err
+	}
+	if specData, err = json.Marshal(c.Spec); err != nil {
+		return nil, err
+	}
+
+	if row := tx.QueryRow(
+		"INSERT INTO data_configs (config_id, version, data, spec) VALUES ($1, $2, $3, $4)",
+		c.ID,
+		c.Version,
+		configData,
+		specData,
+	); row.Err() != nil {
+		return nil, row.Err()
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// ListVersions returns up to limit versions of the service config older than
+// beforeVersion, or the latest ones if beforeVersion is 0, newest first.
+func (r *ServiceConfigRepository) ListVersions(c *models.ServiceConfig, beforeVersion uint32, limit int) ([]*models.VersionInfo, error) {
+
+	if err := r.psql.conn().QueryRow("SELECT id FROM configs WHERE service=$1",
+		c.Service,
+	).Scan(&c.ID); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceConfig, Name: c.Service, Msg: getConfigForServiceNotFoundError(c.Service)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.psql.conn().Query("SELECT version, octet_length(data::text) + octet_length(spec::text), md5(data::text || spec::text), created_at, "+
+		"message, COALESCE(rollback_of, 0), COALESCE(changeset_id, 0) "+
+		"FROM data_configs WHERE (config_id=$1) AND ($2=0 OR version<$2) ORDER BY version DESC LIMIT $3",
+		c.ID,
+		beforeVersion,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []*models.VersionInfo
+	for rows.Next() {
+		v := &models.VersionInfo{}
+		if err = rows.Scan(&v.Version, &v.Size, &v.Checksum, &v.CreatedAt, &v.Message, &v.RollbackOf, &v.ChangesetID); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// Rollback creates a new version of the service config with the data and spec
+// of rb.ToVersion, recorded as a rollback of that version, and moves rb.Tag to
+// it if set.
+func (r *ServiceConfigRepository) Rollback(rb *models.Rollback) (*models.ServiceConfig, error) {
+	c := &models.ServiceConfig{Service: rb.Service}
+
+	tx, err := r.psql.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow("SELECT id FROM configs WHERE service=$1 FOR UPDATE",
+		c.Service,
+	).Scan(&c.ID); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceConfig, Name: c.Service, Msg: getConfigForServiceNotFoundError(c.Service)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	var configData, specData []byte
+	if err = tx.QueryRow("SELECT data, spec FROM data_configs WHERE (config_id=$1) AND (version=$2)",
+		c.ID,
+		rb.ToVersion,
+	).Scan(&configData, &specData); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceConfigVersion, Name: c.Service, Msg: getConfigVersionNotFoundError(c.Service, rb.ToVersion)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err = tx.QueryRow("SELECT version FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1",
+		c.ID,
+	).Scan(&c.Version); err != nil {
+		return nil, err
+	}
+	c.Version++
+
+	if row := tx.QueryRow(
+		"INSERT INTO data_configs (config_id, version, data, spec, message, rollback_of) VALUES ($1, $2, $3, $4, $5, $6)",
+		c.ID,
+		c.Version,
+		configData,
+		specData,
+		rb.Message,
+		rb.ToVersion,
+	); row.Err() != nil {
+		return nil, row.Err()
+	}
+
+	if rb.Tag != "" {
+		if err = r.psql.Tag().move(tx, &models.Tag{Service: c.Service, Name: rb.Tag, Version: c.Version}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(configData, &c.Data); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(specData, &c.Spec); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
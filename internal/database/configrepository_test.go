@@ -8,6 +8,7 @@ import (
 	"github.com/wphylici/contest-cloud/internal/models"
 	"regexp"
 	"testing"
+	"time"
 )
 
 func TestCreate(t *testing.T) {
@@ -75,9 +76,14 @@ func TestCreate(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				query = regexp.QuoteMeta("INSERT INTO data_configs (config_id, version, data) VALUES ($1, $2, $3)")
+				spec, err := json.Marshal(args.sc.Spec)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				query = regexp.QuoteMeta("INSERT INTO data_configs (config_id, version, data, spec) VALUES ($1, $2, $3, $4)")
 				mock.ExpectQuery(query).
-					WithArgs(args.sc.ID, args.sc.Version, data).WillReturnRows(&sqlmock.Rows{})
+					WithArgs(args.sc.ID, args.sc.Version, data, spec).WillReturnRows(&sqlmock.Rows{})
 
 				mock.ExpectCommit()
 			},
@@ -189,8 +195,8 @@ func TestRead(t *testing.T) {
 				mock.ExpectQuery(query).
 					WithArgs(args.sc.Service).WillReturnRows(rows)
 
-				rows = mock.NewRows([]string{"data", "version"}).AddRow(configData, 1)
-				query = regexp.QuoteMeta("SELECT data, version FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
+				rows = mock.NewRows([]string{"data", "spec", "version"}).AddRow(configData, []byte("{}"), 1)
+				query = regexp.QuoteMeta("SELECT data, spec, version FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
 				mock.ExpectQuery(query).
 					WithArgs(1).WillReturnRows(rows)
 			},
@@ -228,8 +234,8 @@ func TestRead(t *testing.T) {
 				mock.ExpectQuery(query).
 					WithArgs(args.sc.Service).WillReturnRows(rows)
 
-				rows = mock.NewRows([]string{"data"}).AddRow(configData)
-				query = regexp.QuoteMeta("SELECT data FROM data_configs WHERE (config_id=$1) AND (version=$2)")
+				rows = mock.NewRows([]string{"data", "spec"}).AddRow(configData, []byte("{}"))
+				query = regexp.QuoteMeta("SELECT data, spec FROM data_configs WHERE (config_id=$1) AND (version=$2)")
 				mock.ExpectQuery(query).
 					WithArgs(1, 1).WillReturnRows(rows)
 			},
@@ -264,7 +270,7 @@ func TestRead(t *testing.T) {
 				mock.ExpectQuery(query).
 					WithArgs(args.sc.Service).WillReturnRows(rows)
 
-				query = regexp.QuoteMeta("SELECT data FROM data_configs WHERE (config_id=$1) AND (version=$2)")
+				query = regexp.QuoteMeta("SELECT data, spec FROM data_configs WHERE (config_id=$1) AND (version=$2)")
 				mock.ExpectQuery(query).
 					WithArgs(1, 2).WillReturnError(sql.ErrNoRows)
 			},
@@ -366,8 +372,8 @@ func TestUpdate(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				rows = mock.NewRows([]string{"version", "data"}).AddRow(1, lastVersionData)
-				query = regexp.QuoteMeta("SELECT version, data FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
+				rows = mock.NewRows([]string{"version", "data", "spec"}).AddRow(1, lastVersionData, []byte("{}"))
+				query = regexp.QuoteMeta("SELECT version, data, spec FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
 				mock.ExpectQuery(query).
 					WithArgs(1).WillReturnRows(rows)
 
@@ -376,9 +382,9 @@ func TestUpdate(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				query = regexp.QuoteMeta("INSERT INTO data_configs (config_id, version, data) VALUES ($1, $2, $3)")
+				query = regexp.QuoteMeta("INSERT INTO data_configs (config_id, version, data, spec) VALUES ($1, $2, $3, $4)")
 				mock.ExpectQuery(query).
-					WithArgs(1, 2, data).WillReturnRows(&sqlmock.Rows{})
+					WithArgs(1, 2, data, []byte("{}")).WillReturnRows(&sqlmock.Rows{})
 			},
 		},
 		{
@@ -431,8 +437,8 @@ func TestUpdate(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				rows = mock.NewRows([]string{"version", "data"}).AddRow(1, lastVersionData)
-				query = regexp.QuoteMeta("SELECT version, data FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
+				rows = mock.NewRows([]string{"version", "data", "spec"}).AddRow(1, lastVersionData, []byte("{}"))
+				query = regexp.QuoteMeta("SELECT version, data, spec FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
 				mock.ExpectQuery(query).
 					WithArgs(1).WillReturnRows(rows)
 			},
@@ -611,3 +617,574 @@ func TestDelete(t *testing.T) {
 	}
 
 }
+
+func TestConfigExpire(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &ServiceConfigRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	now := time.Date(2026, 10, 23, 18, 0, 0, 0, time.UTC)
+
+	type args struct {
+		c *models.ServiceConfig
+	}
+	type mockBehavior func(args args, data string, spec string)
+
+	expectLatest := func(args args, data string, spec string) {
+		mock.ExpectBegin()
+
+		rows := mock.NewRows([]string{"id"}).AddRow(1)
+		query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1 FOR UPDATE")
+		mock.ExpectQuery(query).
+			WithArgs(args.c.Service).WillReturnRows(rows)
+
+		rows = mock.NewRows([]string{"version", "data", "spec"}).AddRow(2, data, spec)
+		query = regexp.QuoteMeta("SELECT version, data, spec FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
+		mock.ExpectQuery(query).
+			WithArgs(1).WillReturnRows(rows)
+	}
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		data         string
+		spec         string
+		expects      *models.ServiceConfig
+		wantError    bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				c: &models.ServiceConfig{Service: "test1"},
+			},
+			data: `{"rate.limit":"500","boost":"on"}`,
+			spec: `{"expires":{"rate.limit":{"at":"2026-10-23T17:00:00Z","prior":"100"},"boost":{"at":"2026-10-23T18:00:00Z"}}}`,
+			expects: &models.ServiceConfig{
+				ID:      1,
+				Service: "test1",
+				Version: 3,
+				Data:    map[string]string{"rate.limit": "100"},
+				Spec:    models.Spec{Expired: []string{"boost", "rate.limit"}},
+			},
+			mockBehavior: func(args args, data string, spec string) {
+				expectLatest(args, data, spec)
+
+				query := regexp.QuoteMeta("INSERT INTO data_configs (config_id, version, data, spec) VALUES ($1, $2, $3, $4)")
+				mock.ExpectQuery(query).
+					WithArgs(1, 3, []byte(`{"rate.limit":"100"}`), []byte(`{"expired":["boost","rate.limit"]}`)).
+					WillReturnRows(&sqlmock.Rows{})
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK Nothing Expired",
+			args: args{
+				c: &models.ServiceConfig{Service: "test1"},
+			},
+			data: `{"rate.limit":"500"}`,
+			spec: `{"expires":{"rate.limit":{"at":"2026-10-24T18:00:00Z","prior":"100"}}}`,
+			mockBehavior: func(args args, data string, spec string) {
+				expectLatest(args, data, spec)
+
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "ConfigForServiceNotFound",
+			args: args{
+				c: &models.ServiceConfig{Service: "test2"},
+			},
+			wantError: true,
+			mockBehavior: func(args args, data string, spec string) {
+				mock.ExpectBegin()
+
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1 FOR UPDATE")
+				mock.ExpectQuery(query).
+					WithArgs(args.c.Service).WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.args, testCase.data, testCase.spec)
+
+			got, err := r.Expire(testCase.args.c, now)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListVersions(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &ServiceConfigRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	createdAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	type args struct {
+		c             *models.ServiceConfig
+		beforeVersion uint32
+		limit         int
+	}
+	type mockBehavior func(args args)
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		expects      []*models.VersionInfo
+		wantError    bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				c:             &models.ServiceConfig{Service: "test1"},
+				beforeVersion: 4,
+				limit:         2,
+			},
+			expects: []*models.VersionInfo{
+				{Version: 3, Size: 42, Checksum: "9e107d9d372bb6826bd81d3542a419d6", CreatedAt: createdAt, Message: "revert timeout", RollbackOf: 1},
+				{Version: 2, Size: 40, Checksum: "e4d909c290d0fb1ca068ffaddf22cbd0", CreatedAt: createdAt.Add(-time.Hour), ChangesetID: 5},
+			},
+			mockBehavior: func(args args) {
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1")
+				mock.ExpectQuery(query).
+					WithArgs(args.c.Service).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"version", "size", "checksum", "created_at", "message", "rollback_of", "changeset_id"}).
+					AddRow(3, 42, "9e107d9d372bb6826bd81d3542a419d6", createdAt, "revert timeout", 1, 0).
+					AddRow(2, 40, "e4d909c290d0fb1ca068ffaddf22cbd0", createdAt.Add(-time.Hour), "", 0, 5)
+				query = regexp.QuoteMeta("SELECT version, octet_length(data::text) + octet_length(spec::text), md5(data::text || spec::text), created_at, " +
+					"message, COALESCE(rollback_of, 0), COALESCE(changeset_id, 0) " +
+					"FROM data_configs WHERE (config_id=$1) AND ($2=0 OR version<$2) ORDER BY version DESC LIMIT $3")
+				mock.ExpectQuery(query).
+					WithArgs(1, args.beforeVersion, args.limit).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "ConfigForServiceNotFound",
+			args: args{
+				c:     &models.ServiceConfig{Service: "test2"},
+				limit: 2,
+			},
+			wantError: true,
+			mockBehavior: func(args args) {
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1")
+				mock.ExpectQuery(query).
+					WithArgs(args.c.Service).WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.args)
+
+			got, err := r.ListVersions(testCase.args.c, testCase.args.beforeVersion, testCase.args.limit)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestConfigReadVersionsAfter(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &ServiceConfigRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	type args struct {
+		c            *models.ServiceConfig
+		afterVersion uint32
+	}
+	type mockBehavior func(args args)
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		expects      []*models.ServiceConfig
+	}{
+		{
+			name: "OK",
+			args: args{
+				c:            &models.ServiceConfig{Service: "test1"},
+				afterVersion: 2,
+			},
+			expects: []*models.ServiceConfig{
+				{ID: 1, Service: "test1", Version: 3, Data: map[string]string{"k1": "v3"}},
+				{ID: 1, Service: "test1", Version: 4, Data: map[string]string{"k1": "v4"}},
+			},
+			mockBehavior: func(args args) {
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1")
+				mock.ExpectQuery(query).
+					WithArgs(args.c.Service).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"version", "data", "spec"}).
+					AddRow(3, []byte(`{"k1":"v3"}`), []byte(`{}`)).
+					AddRow(4, []byte(`{"k1":"v4"}`), []byte(`{}`))
+				query = regexp.QuoteMeta("SELECT version, data, spec FROM data_configs WHERE (config_id=$1) AND (version>$2) ORDER BY version")
+				mock.ExpectQuery(query).
+					WithArgs(1, args.afterVersion).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "ConfigForServiceNotFound",
+			args: args{
+				c:            &models.ServiceConfig{Service: "test2"},
+				afterVersion: 2,
+			},
+			mockBehavior: func(args args) {
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1")
+				mock.ExpectQuery(query).
+					WithArgs(args.c.Service).WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.args)
+
+			got, err := r.ReadVersionsAfter(testCase.args.c, testCase.args.afterVersion)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expects, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestConfigRollback(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &ServiceConfigRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	configData := []byte(`{"key1":"value1"}`)
+	specData := []byte(`{}`)
+
+	type mockBehavior func(rb *models.Rollback)
+
+	expectTarget := func(rb *models.Rollback) {
+		rows := mock.NewRows([]string{"id"}).AddRow(1)
+		query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1 FOR UPDATE")
+		mock.ExpectQuery(query).
+			WithArgs(rb.Service).WillReturnRows(rows)
+
+		rows = mock.NewRows([]string{"data", "spec"}).AddRow(configData, specData)
+		query = regexp.QuoteMeta("SELECT data, spec FROM data_configs WHERE (config_id=$1) AND (version=$2)")
+		mock.ExpectQuery(query).
+			WithArgs(1, rb.ToVersion).WillReturnRows(rows)
+
+		rows = mock.NewRows([]string{"version"}).AddRow(3)
+		query = regexp.QuoteMeta("SELECT version FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
+		mock.ExpectQuery(query).
+			WithArgs(1).WillReturnRows(rows)
+
+		query = regexp.QuoteMeta("INSERT INTO data_configs (config_id, version, data, spec, message, rollback_of) VALUES ($1, $2, $3, $4, $5, $6)")
+		mock.ExpectQuery(query).
+			WithArgs(1, 4, configData, specData, rb.Message, rb.ToVersion).WillReturnRows(&sqlmock.Rows{})
+	}
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		rollback     *models.Rollback
+		expects      *models.ServiceConfig
+		wantError    bool
+	}{
+		{
+			name:     "OK",
+			rollback: &models.Rollback{Service: "test1", ToVersion: 1, Message: "bad timeout"},
+			expects: &models.ServiceConfig{
+				ID:      1,
+				Service: "test1",
+				Version: 4,
+				Data:    map[string]string{"key1": "value1"},
+			},
+			mockBehavior: func(rb *models.Rollback) {
+				mock.ExpectBegin()
+				expectTarget(rb)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "OK Move Tag",
+			rollback: &models.Rollback{Service: "test1", ToVersion: 1, Tag: "prod"},
+			expects: &models.ServiceConfig{
+				ID:      1,
+				Service: "test1",
+				Version: 4,
+				Data:    map[string]string{"key1": "value1"},
+			},
+			mockBehavior: func(rb *models.Rollback) {
+				mock.ExpectBegin()
+				expectTarget(rb)
+
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1")
+				mock.ExpectQuery(query).
+					WithArgs(rb.Service).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"exist"}).AddRow(true)
+				query = regexp.QuoteMeta("SELECT EXISTS(SELECT version FROM data_configs WHERE (config_id=$1) AND (version=$2))")
+				mock.ExpectQuery(query).
+					WithArgs(1, 4).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"version", "movable"}).AddRow(3, true)
+				query = regexp.QuoteMeta("SELECT version, movable FROM tags WHERE (config_id=$1) AND (name=$2) FOR UPDATE")
+				mock.ExpectQuery(query).
+					WithArgs(1, rb.Tag).WillReturnRows(rows)
+
+				query = regexp.QuoteMeta("UPDATE tags SET version=$1 WHERE (config_id=$2) AND (name=$3)")
+				mock.ExpectQuery(query).
+					WithArgs(4, 1, rb.Tag).WillReturnRows(&sqlmock.Rows{})
+
+				query = regexp.QuoteMeta("INSERT INTO tag_history (config_id, name, old_version, new_version) VALUES ($1, $2, $3, $4)")
+				mock.ExpectQuery(query).
+					WithArgs(1, rb.Tag, 3, 4).WillReturnRows(&sqlmock.Rows{})
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:      "TagIsImmutable",
+			rollback:  &models.Rollback{Service: "test1", ToVersion: 1, Tag: "stable"},
+			wantError: true,
+			mockBehavior: func(rb *models.Rollback) {
+				mock.ExpectBegin()
+				expectTarget(rb)
+
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1")
+				mock.ExpectQuery(query).
+					WithArgs(rb.Service).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"exist"}).AddRow(true)
+				query = regexp.QuoteMeta("SELECT EXISTS(SELECT version FROM data_configs WHERE (config_id=$1) AND (version=$2))")
+				mock.ExpectQuery(query).
+					WithArgs(1, 4).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"version", "movable"}).AddRow(3, false)
+				query = regexp.QuoteMeta("SELECT version, movable FROM tags WHERE (config_id=$1) AND (name=$2) FOR UPDATE")
+				mock.ExpectQuery(query).
+					WithArgs(1, rb.Tag).WillReturnRows(rows)
+
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:      "ConfigVersionNotFound",
+			rollback:  &models.Rollback{Service: "test1", ToVersion: 7},
+			wantError: true,
+			mockBehavior: func(rb *models.Rollback) {
+				mock.ExpectBegin()
+
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1 FOR UPDATE")
+				mock.ExpectQuery(query).
+					WithArgs(rb.Service).WillReturnRows(rows)
+
+				query = regexp.QuoteMeta("SELECT data, spec FROM data_configs WHERE (config_id=$1) AND (version=$2)")
+				mock.ExpectQuery(query).
+					WithArgs(1, rb.ToVersion).WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.rollback)
+
+			got, err := r.Rollback(testCase.rollback)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestConfigUpdateFrom(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &ServiceConfigRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	type args struct {
+		c           *models.ServiceConfig
+		baseVersion uint32
+	}
+	type mockBehavior func(args args)
+
+	lastConfigData := []byte(`{"key1":"value1"}`)
+	specData := []byte(`{}`)
+
+	expectLatest := func(args args) {
+		rows := mock.NewRows([]string{"id"}).AddRow(1)
+		query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1 FOR UPDATE")
+		mock.ExpectQuery(query).
+			WithArgs(args.c.Service).WillReturnRows(rows)
+
+		rows = mock.NewRows([]string{"version", "data", "spec"}).AddRow(2, lastConfigData, specData)
+		query = regexp.QuoteMeta("SELECT version, data, spec FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
+		mock.ExpectQuery(query).
+			WithArgs(1).WillReturnRows(rows)
+	}
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		expects      *models.ServiceConfig
+		wantError    bool
+		expectsError error
+	}{
+		{
+			name: "OK",
+			args: args{
+				c: &models.ServiceConfig{
+					Service: "test1",
+					Data:    map[string]string{"key1": "changed"},
+					Message: "bump key1",
+				},
+				baseVersion: 2,
+			},
+			expects: &models.ServiceConfig{
+				ID:      1,
+				Service: "test1",
+				Version: 3,
+				Data:    map[string]string{"key1": "changed"},
+				Message: "bump key1",
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				expectLatest(args)
+
+				query := regexp.QuoteMeta("INSERT INTO data_configs (config_id, version, data, spec, message, changeset_id) VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0))")
+				mock.ExpectQuery(query).
+					WithArgs(1, 3, []byte(`{"key1":"changed"}`), specData, args.c.Message, 0).WillReturnRows(&sqlmock.Rows{})
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ConfigVersionConflict",
+			args: args{
+				c: &models.ServiceConfig{
+					Service: "test1",
+					Data:    map[string]string{"key1": "changed"},
+				},
+				baseVersion: 1,
+			},
+			wantError: true,
+			expectsError: &PreconditionError{
+				Type:           PreconditionVersionConflict,
+				Subject:        "test1",
+				CurrentVersion: 2,
+				Msg:            getConfigVersionConflictError("test1", 2, 1),
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				expectLatest(args)
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "NoChangeInConfig",
+			args: args{
+				c: &models.ServiceConfig{
+					Service: "test1",
+					Data:    map[string]string{"key1": "value1"},
+				},
+				baseVersion: 2,
+			},
+			wantError: true,
+			expectsError: &PreconditionError{
+				Type:    PreconditionNoChange,
+				Subject: "test1",
+				Msg:     getNoChangeInConfigError(),
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				expectLatest(args)
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.args)
+
+			got, err := r.UpdateFrom(testCase.args.c, testCase.args.baseVersion)
+			if testCase.wantError {
+				assert.Equal(t, testCase.expectsError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
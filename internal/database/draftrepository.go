@@ -0,0 +1,195 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"reflect"
+)
+
+type DraftRepository struct {
+	psql *PostgreSQL
+}
+
+func getDraftNotFoundError(serviceName string, draftID int) string {
+	return fmt.Sprintf("draft '%d' for '%s' service not found", draftID, serviceName)
+}
+
+func getDraftIsOutdatedError(draftID int, baseVersion uint32, latestVersion uint32) string {
+	return fmt.Sprintf("draft '%d' is based on version '%d' but the latest version is '%d'", draftID, baseVersion, latestVersion)
+}
+
+// Create saves the draft based on the latest published version of the
+// service config.
+func (r *DraftRepository) Create(d *models.Draft) (*models.Draft, error) {
+	c := d.Config
+
+	if err := r.psql.conn().QueryRow("SELECT id FROM configs WHERE service=$1",
+		c.Service,
+	).Scan(&c.ID); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceConfig, Name: c.Service, Msg: getConfigForServiceNotFoundError(c.Service)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	configData, err := json.Marshal(c.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	specData, err := json.Marshal(c.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.psql.conn().QueryRow("INSERT INTO draft_configs (config_id, base_version, data, spec) "+
+		"VALUES ($1, (SELECT MAX(version) FROM data_configs WHERE config_id=$1), $2, $3) RETURNING id, base_version, created_at",
+		c.ID,
+		configData,
+		specData,
+	).Scan(&d.ID, &d.BaseVersion, &d.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (r *DraftRepository) Read(d *models.Draft) (*models.Draft, error) {
+	c := d.Config
+
+	var configData, specData []byte
+	if err := r.psql.conn().QueryRow("SELECT d.config_id, d.base_version, d.data, d.spec, d.created_at FROM draft_configs d "+
+		"JOIN configs c ON c.id = d.config_id WHERE (c.service=$1) AND (d.id=$2)",
+		c.Service,
+		d.ID,
+	).Scan(&c.ID, &d.BaseVersion, &configData, &specData, &d.CreatedAt); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceDraft, Name: c.Service, Msg: getDraftNotFoundError(c.Service, d.ID)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(configData, &c.Data); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(specData, &c.Spec); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (r *DraftRepository) List(serviceName string) ([]*models.Draft, error) {
+	rows, err := r.psql.conn().Query("SELECT d.id, d.base_version, d.created_at FROM draft_configs d "+
+		"JOIN configs c ON c.id = d.config_id WHERE c.service=$1 ORDER BY d.id",
+		serviceName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drafts []*models.Draft
+	for rows.Next() {
+		d := &models.Draft{
+			Config: &models.ServiceConfig{Service: serviceName},
+		}
+		if err = rows.Scan(&d.ID, &d.BaseVersion, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return drafts, nil
+}
+
+// Publish makes the draft the latest version of the service config and
+// removes it from the drafts. It fails if another version has been published
+// since the draft was created.
+func (r *DraftRepository) Publish(d *models.Draft) (*models.ServiceConfig, error) {
+	c := d.Config
+
+	tx, err := r.psql.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow("SELECT id FROM configs WHERE service=$1 FOR UPDATE",
+		c.Service,
+	).Scan(&c.ID); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceConfig, Name: c.Service, Msg: getConfigForServiceNotFoundError(c.Service)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	var configData, specData []byte
+	if err = tx.QueryRow("DELETE FROM draft_configs WHERE (config_id=$1) AND (id=$2) RETURNING base_version, data, spec",
+		c.ID,
+		d.ID,
+	).Scan(&d.BaseVersion, &configData, &specData); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceDraft, Name: c.Service, Msg: getDraftNotFoundError(c.Service, d.ID)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	var lastConfigData, lastSpecData []byte
+	if err = tx.QueryRow("SELECT version, data, spec FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1",
+		c.ID,
+	).Scan(&c.Version, &lastConfigData, &lastSpecData); err != nil {
+		return nil, err
+	}
+
+	if c.Version != d.BaseVersion {
+		return nil, &PreconditionError{Type: PreconditionDraftOutdated, Subject: c.Service, CurrentVersion: c.Version, Msg: getDraftIsOutdatedError(d.ID, d.BaseVersion, c.Version)}
+	}
+	if reflect.DeepEqual(lastConfigData, configData) && reflect.DeepEqual(lastSpecData, specData) {
+		return nil, &PreconditionError{Type: PreconditionNoChange, Subject: c.Service, Msg: getNoChangeInConfigError()}
+	}
+	c.Version++
+
+	if row := tx.QueryRow(
+		"INSERT INTO data_configs (config_id, version, data, spec) VALUES ($1, $2, $3, $4)",
+		c.ID,
+		c.Version,
+		configData,
+		specData,
+	); row.Err() != nil {
+		return nil, row.Err()
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(configData, &c.Data); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(specData, &c.Spec); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (r *DraftRepository) Discard(d *models.Draft) (*models.Draft, error) {
+	c := d.Config
+
+	if err := r.psql.conn().QueryRow("DELETE FROM draft_configs d USING configs c "+
+		"WHERE (c.id = d.config_id) AND (c.service=$1) AND (d.id=$2) RETURNING d.id",
+		c.Service,
+		d.ID,
+	).Scan(&d.ID); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceDraft, Name: c.Service, Msg: getDraftNotFoundError(c.Service, d.ID)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
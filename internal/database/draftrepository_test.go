@@ -0,0 +1,137 @@
+package database
+
+import (
+	"database/sql"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"regexp"
+	"testing"
+)
+
+func TestDraftPublish(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &DraftRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	type args struct {
+		d *models.Draft
+	}
+	type mockBehavior func(args args)
+
+	lockQuery := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1 FOR UPDATE")
+	deleteQuery := regexp.QuoteMeta("DELETE FROM draft_configs WHERE (config_id=$1) AND (id=$2) RETURNING base_version, data, spec")
+	latestQuery := regexp.QuoteMeta("SELECT version, data, spec FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		expectsv     *models.ServiceConfig
+		wantError    bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				d: &models.Draft{ID: 7, Config: &models.ServiceConfig{Service: "test1"}},
+			},
+			expectsv: &models.ServiceConfig{
+				ID:      1,
+				Version: 3,
+				Service: "test1",
+				Data:    map[string]string{"key1": "value2"},
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery(lockQuery).
+					WithArgs(args.d.Config.Service).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"base_version", "data", "spec"}).
+					AddRow(2, []byte(`{"key1":"value2"}`), []byte(`{}`))
+				mock.ExpectQuery(deleteQuery).
+					WithArgs(1, args.d.ID).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"version", "data", "spec"}).
+					AddRow(2, []byte(`{"key1":"value1"}`), []byte(`{}`))
+				mock.ExpectQuery(latestQuery).
+					WithArgs(1).WillReturnRows(rows)
+
+				query := regexp.QuoteMeta("INSERT INTO data_configs (config_id, version, data, spec) VALUES ($1, $2, $3, $4)")
+				mock.ExpectQuery(query).
+					WithArgs(1, 3, []byte(`{"key1":"value2"}`), []byte(`{}`)).WillReturnRows(&sqlmock.Rows{})
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "DraftIsOutdated",
+			args: args{
+				d: &models.Draft{ID: 7, Config: &models.ServiceConfig{Service: "test1"}},
+			},
+			wantError: true,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery(lockQuery).
+					WithArgs(args.d.Config.Service).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"base_version", "data", "spec"}).
+					AddRow(2, []byte(`{"key1":"value2"}`), []byte(`{}`))
+				mock.ExpectQuery(deleteQuery).
+					WithArgs(1, args.d.ID).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"version", "data", "spec"}).
+					AddRow(3, []byte(`{"key1":"value3"}`), []byte(`{}`))
+				mock.ExpectQuery(latestQuery).
+					WithArgs(1).WillReturnRows(rows)
+
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "DraftNotFound",
+			args: args{
+				d: &models.Draft{ID: 8, Config: &models.ServiceConfig{Service: "test1"}},
+			},
+			wantError: true,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery(lockQuery).
+					WithArgs(args.d.Config.Service).WillReturnRows(rows)
+
+				mock.ExpectQuery(deleteQuery).
+					WithArgs(1, args.d.ID).WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.args)
+
+			got, err := r.Publish(testCase.args.d)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expectsv, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package database
+
+const (
+	ResourceConfig             = "config"
+	ResourceConfigVersion      = "config version"
+	ResourceDraft              = "draft"
+	ResourceKeyMetadata        = "key metadata"
+	ResourceKeyMetadataVersion = "key metadata version"
+	ResourceSchema             = "schema"
+	ResourceSchemaVersion      = "schema version"
+	ResourceTag                = "tag"
+	ResourceTemplate           = "template"
+	ResourceTemplateVersion    = "template version"
+)
+
+const (
+	PreconditionNoChange        = "NO_CHANGE"
+	PreconditionVersionConflict = "VERSION_CONFLICT"
+	PreconditionDraftOutdated   = "DRAFT_OUTDATED"
+	PreconditionTagImmutable    = "TAG_IMMUTABLE"
+)
+
+// NotFoundError is returned when the named resource does not exist.
+type NotFoundError struct {
+	Resource string
+	Name     string
+	Msg      string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Msg
+}
+
+// AlreadyExistsError is returned when creating a resource that exists.
+type AlreadyExistsError struct {
+	Resource string
+	Name     string
+	Msg      string
+}
+
+func (e *AlreadyExistsError) Error() string {
+	return e.Msg
+}
+
+// PreconditionError is returned when the current state of the subject does
+// not allow the operation. CurrentVersion is the latest version of the subject
+// when the precondition is about versions.
+type PreconditionError struct {
+	Type           string
+	Subject        string
+	CurrentVersion uint32
+	Msg            string
+}
+
+func (e *PreconditionError) Error() string {
+	return e.Msg
+}
+
+// InvalidArgumentError is returned when the value of Field is not valid.
+type InvalidArgumentError struct {
+	Field string
+	Msg   string
+}
+
+func (e *InvalidArgumentError) Error() string {
+	return e.Msg
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"database/sql"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"time"
+)
+
+// IdempotencyRepository stores the keys of requests together with their
+// response. Its methods, except Purge, are meant to run in the transaction of
+// the request, so that a key is stored if and only if the request is applied.
+type IdempotencyRepository struct {
+	psql *PostgreSQL
+}
+
+// Lock takes the key for the rest of the transaction. It returns false if
+// another transaction holds it, which means the same request is in progress.
+func (r *IdempotencyRepository) Lock(k *models.IdempotencyKey) (bool, error) {
+	var locked bool
+	if err := r.psql.conn().QueryRow("SELECT pg_try_advisory_xact_lock(hashtextextended($1, 0))",
+		k.Key,
+	).Scan(&locked); err != nil {
+		return false, err
+	}
+
+	return locked, nil
+}
+
+// Read returns the stored key or nil if the key was not used within
+// retention.
+func (r *IdempotencyRepository) Read(k *models.IdempotencyKey, retention time.Duration) (*models.IdempotencyKey, error) {
+	stored := &models.IdempotencyKey{Key: k.Key}
+	if err := r.psql.conn().QueryRow("SELECT method, request_hash, response, created_at FROM idempotency_keys "+
+		"WHERE (key=$1) AND (created_at >= now() - $2 * interval '1 second')",
+		k.Key,
+		int64(retention/time.Second),
+	).Scan(&stored.Method, &stored.RequestHash, &stored.Response, &stored.CreatedAt); err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// Create stores the key with the response of its request, replacing the key
+// if it was used before retention.
+func (r *IdempotencyRepository) Create(k *models.IdempotencyKey) error {
+	if row := r.psql.conn().QueryRow("INSERT INTO idempotency_keys (key, method, request_hash, response) VALUES ($1, $2, $3, $4) "+
+		"ON CONFLICT (key) DO UPDATE SET method=EXCLUDED.method, request_hash=EXCLUDED.request_hash, "+
+		"response=EXCLUDED.response, created_at=now()",
+		k.Key,
+		k.Method,
+		k.RequestHash,
+		k.Response,
+	); row.Err() != nil {
+		return row.Err()
+	}
+
+	return nil
+}
+
+// Purge removes the keys older than retention.
+func (r *IdempotencyRepository) Purge(retention time.Duration) error {
+	if row := r.psql.conn().QueryRow("DELETE FROM idempotency_keys WHERE created_at < now() - $1 * interval '1 second'",
+		int64(retention/time.Second),
+	); row.Err() != nil {
+		return row.Err()
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyRead(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &IdempotencyRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	type mockBehavior func(k *models.IdempotencyKey)
+
+	createdAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("SELECT method, request_hash, response, created_at FROM idempotency_keys " +
+		"WHERE (key=$1) AND (created_at >= now() - $2 * interval '1 second')")
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		key          *models.IdempotencyKey
+		expects      *models.IdempotencyKey
+	}{
+		{
+			name: "Stored",
+			key:  &models.IdempotencyKey{Key: "deploy-42", Method: "/ConfigController/Create", RequestHash: "abc"},
+			expects: &models.IdempotencyKey{
+				Key:         "deploy-42",
+				Method:      "/ConfigController/Create",
+				RequestHash: "abc",
+				Response:    []byte("response"),
+				CreatedAt:   createdAt,
+			},
+			mockBehavior: func(k *models.IdempotencyKey) {
+				rows := mock.NewRows([]string{"method", "request_hash", "response", "created_at"}).
+					AddRow(k.Method, k.RequestHash, []byte("response"), createdAt)
+				mock.ExpectQuery(query).
+					WithArgs(k.Key, 86400).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "NotStored",
+			key:  &models.IdempotencyKey{Key: "deploy-43", Method: "/ConfigController/Create", RequestHash: "abc"},
+			mockBehavior: func(k *models.IdempotencyKey) {
+				mock.ExpectQuery(query).
+					WithArgs(k.Key, 86400).WillReturnRows(mock.NewRows([]string{"method", "request_hash", "response", "created_at"}))
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.key)
+
+			got, err := r.Read(testCase.key, 24*time.Hour)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expects, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIdempotencyInRequestTransaction(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	k := &models.IdempotencyKey{
+		Key:         "deploy-42",
+		Method:      "/ConfigController/Create",
+		RequestHash: "abc",
+		Response:    []byte("response"),
+	}
+
+	mock.ExpectBegin()
+
+	rows := mock.NewRows([]string{"locked"}).AddRow(true)
+	query := regexp.QuoteMeta("SELECT pg_try_advisory_xact_lock(hashtextextended($1, 0))")
+	mock.ExpectQuery(query).
+		WithArgs(k.Key).WillReturnRows(rows)
+
+	// a repository transaction becomes a savepoint of the request transaction
+	mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	query = regexp.QuoteMeta("INSERT INTO idempotency_keys (key, method, request_hash, response) VALUES ($1, $2, $3, $4) " +
+		"ON CONFLICT (key) DO UPDATE SET method=EXCLUDED.method, request_hash=EXCLUDED.request_hash, " +
+		"response=EXCLUDED.response, created_at=now()")
+	mock.ExpectQuery(query).
+		WithArgs(k.Key, k.Method, k.RequestHash, k.Response).WillReturnRows(&sqlmock.Rows{})
+
+	mock.ExpectCommit()
+
+	psql, err := (&PostgreSQL{db: dbmock}).Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer psql.Rollback()
+
+	locked, err := psql.Idempotency().Lock(k)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+
+	tx, err := psql.begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.Error(t, tx.Rollback())
+
+	assert.NoError(t, psql.Idempotency().Create(k))
+	assert.NoError(t, psql.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyPurge(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &IdempotencyRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	query := regexp.QuoteMeta("DELETE FROM idempotency_keys WHERE created_at < now() - $1 * interval '1 second'")
+	mock.ExpectQuery(query).
+		WithArgs(3600).WillReturnRows(&sqlmock.Rows{})
+
+	assert.NoError(t, r.Purge(time.Hour))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/models"
+)
+
+type KeyMetadataRepository struct {
+	psql *PostgreSQL
+}
+
+func getKeyMetadataForServiceNotFoundError(serviceName string) string {
+	return fmt.Sprintf("key metadata for service '%s' not found", serviceName)
+}
+
+func getKeyMetadataVersionNotFoundError(serviceName string, version uint32) string {
+	return fmt.Sprintf("key metadata version '%d' for '%s' service not found", version, serviceName)
+}
+
+func (r *KeyMetadataRepository) Create(m *models.KeyMetadata) (*models.KeyMetadata, error) {
+	tx, err := r.psql.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var configID int
+	if err = tx.QueryRow("SELECT id FROM configs WHERE service=$1 FOR UPDATE",
+		m.Service,
+	).Scan(&configID); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceConfig, Name: m.Service, Msg: getConfigForServiceNotFoundError(m.Service)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err = tx.QueryRow("SELECT COALESCE(MAX(version), 0) FROM key_metadata WHERE config_id=$1",
+		configID,
+	).Scan(&m.Version); err != nil {
+		return nil, err
+	}
+	m.Version++
+
+	metadata, err := json.Marshal(m.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if row := tx.QueryRow(
+		"INSERT INTO key_metadata (config_id, version, metadata) VALUES ($1, $2, $3)",
+		configID,
+		m.Version,
+		metadata,
+	); row.Err() != nil {
+		return nil, row.Err()
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (r *KeyMetadataRepository) Read(m *models.KeyMetadata) (*models.KeyMetadata, error) {
+
+	if m.Version == 0 {
+		latest, err := r.Latest(m.Service)
+		if err != nil {
+			return nil, err
+		} else if latest == nil {
+			return nil, &NotFoundError{Resource: ResourceKeyMetadata, Name: m.Service, Msg: getKeyMetadataForServiceNotFoundError(m.Service)}
+		}
+		return latest, nil
+	}
+
+	var metadata []byte
+	if err := r.psql.conn().QueryRow("SELECT m.metadata FROM key_metadata m JOIN configs c ON c.id=m.config_id WHERE (c.service=$1) AND (m.version=$2)",
+		m.Service,
+		m.Version,
+	).Scan(&metadata); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceKeyMetadataVersion, Name: m.Service, Msg: getKeyMetadataVersionNotFoundError(m.Service, m.Version)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(metadata, &m.Keys); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Latest returns the latest key metadata of the service or nil if the service
+// has none.
+func (r *KeyMetadataRepository) Latest(serviceName string) (*models.KeyMetadata, error) {
+	m := &models.KeyMetadata{Service: serviceName}
+
+	var metadata []byte
+	if err := r.psql.conn().QueryRow("SELECT m.metadata, m.version FROM key_metadata m JOIN configs c ON c.id=m.config_id WHERE c.service=$1 ORDER BY m.version DESC LIMIT 1",
+		m.Service,
+	).Scan(&metadata, &m.Version); err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(metadata, &m.Keys); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
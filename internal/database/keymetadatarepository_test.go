@@ -0,0 +1,141 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"regexp"
+	"testing"
+)
+
+func TestKeyMetadataCreate(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &KeyMetadataRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	type args struct {
+		m *models.KeyMetadata
+	}
+	type mockBehavior func(args args)
+
+	keys := map[string]models.KeyInfo{
+		"db.host": {Description: "database host", Owner: "team-storage"},
+		"db.pool": {Deprecated: "use db.pool.max"},
+	}
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		expects      *models.KeyMetadata
+		wantError    bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				m: &models.KeyMetadata{Service: "test1", Keys: keys},
+			},
+			expects: &models.KeyMetadata{Service: "test1", Version: 2, Keys: keys},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1 FOR UPDATE")
+				mock.ExpectQuery(query).
+					WithArgs(args.m.Service).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"max"}).AddRow(1)
+				query = regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0) FROM key_metadata WHERE config_id=$1")
+				mock.ExpectQuery(query).
+					WithArgs(1).WillReturnRows(rows)
+
+				data, err := json.Marshal(args.m.Keys)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				query = regexp.QuoteMeta("INSERT INTO key_metadata (config_id, version, metadata) VALUES ($1, $2, $3)")
+				mock.ExpectQuery(query).
+					WithArgs(1, 2, data).WillReturnRows(&sqlmock.Rows{})
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ConfigForServiceNotFound",
+			args: args{
+				m: &models.KeyMetadata{Service: "test2", Keys: keys},
+			},
+			wantError: true,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1 FOR UPDATE")
+				mock.ExpectQuery(query).
+					WithArgs(args.m.Service).WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.args)
+
+			got, err := r.Create(testCase.args.m)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestKeyMetadataLatest(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &KeyMetadataRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	query := regexp.QuoteMeta("SELECT m.metadata, m.version FROM key_metadata m JOIN configs c ON c.id=m.config_id WHERE c.service=$1 ORDER BY m.version DESC LIMIT 1")
+
+	rows := mock.NewRows([]string{"metadata", "version"}).AddRow(`{"db.pool":{"deprecated":"use db.pool.max"}}`, 4)
+	mock.ExpectQuery(query).WithArgs("test1").WillReturnRows(rows)
+
+	got, err := r.Latest("test1")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.KeyMetadata{
+		Service: "test1",
+		Version: 4,
+		Keys:    map[string]models.KeyInfo{"db.pool": {Deprecated: "use db.pool.max"}},
+	}, got)
+
+	mock.ExpectQuery(query).WithArgs("test2").WillReturnError(sql.ErrNoRows)
+
+	got, err = r.Latest("test2")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -10,7 +10,17 @@ var Psql *PostgreSQL
 type PostgreSQL struct {
 	config                  *Config
 	db                      *sql.DB
+	tx                      *sql.Tx
+	savepoints              int
 	serviceConfigRepository *ServiceConfigRepository
+	schemaRepository        *SchemaRepository
+	templateRepository      *TemplateRepository
+	serviceRepository       *ServiceRepository
+	tagRepository           *TagRepository
+	draftRepository         *DraftRepository
+	keyMetadataRepository   *KeyMetadataRepository
+	changesetRepository     *ChangesetRepository
+	idempotencyRepository   *IdempotencyRepository
 }
 
 func New(config *Config) *PostgreSQL {
@@ -19,6 +29,13 @@ func New(config *Config) *PostgreSQL {
 	}
 }
 
+// NewFromDB returns a PostgreSQL on an open database.
+func NewFromDB(db *sql.DB) *PostgreSQL {
+	return &PostgreSQL{
+		db: db,
+	}
+}
+
 func (p *PostgreSQL) Open() error {
 
 	db, err := sql.Open("postgres", p.config.DatabaseURL)
@@ -50,3 +67,99 @@ func (p *PostgreSQL) ServiceConfig() *ServiceConfigRepository {
 
 	return p.serviceConfigRepository
 }
+
+func (p *PostgreSQL) Schema() *SchemaRepository {
+	if p.schemaRepository != nil {
+		return p.schemaRepository
+	}
+
+	p.schemaRepository = &SchemaRepository{
+		psql: p,
+	}
+
+	return p.schemaRepository
+}
+
+func (p *PostgreSQL) Template() *TemplateRepository {
+	if p.templateRepository != nil {
+		return p.templateRepository
+	}
+
+	p.templateRepository = &TemplateRepository{
+		psql: p,
+	}
+
+	return p.templateRepository
+}
+
+func (p *PostgreSQL) Service() *ServiceRepository {
+	if p.serviceRepository != nil {
+		return p.serviceRepository
+	}
+
+	p.serviceRepository = &ServiceRepository{
+		psql: p,
+	}
+
+	return p.serviceRepository
+}
+
+func (p *PostgreSQL) Tag() *TagRepository {
+	if p.tagRepository != nil {
+		return p.tagRepository
+	}
+
+	p.tagRepository = &TagRepository{
+		psql: p,
+	}
+
+	return p.tagRepository
+}
+
+func (p *PostgreSQL) Draft() *DraftRepository {
+	if p.draftRepository != nil {
+		return p.draftRepository
+	}
+
+	p.draftRepository = &DraftRepository{
+		psql: p,
+	}
+
+	return p.draftRepository
+}
+
+func (p *PostgreSQL) KeyMetadata() *KeyMetadataRepository {
+	if p.keyMetadataRepository != nil {
+		return p.keyMetadataRepository
+	}
+
+	p.keyMetadataRepository = &KeyMetadataRepository{
+		psql: p,
+	}
+
+	return p.keyMetadataRepository
+}
+
+func (p *PostgreSQL) Changeset() *ChangesetRepository {
+	if p.changesetRepository != nil {
+		return p.changesetRepository
+	}
+
+	p.changesetRepository = &ChangesetRepository{
+		psql: p,
+	}
+
+	return p.changesetRepository
+}
+
+func (p *PostgreSQL) Idempotency() *IdempotencyRepository {
+	if p.idempotencyRepository != nil {
+		return p.idempotencyRepository
+	}
+
+	p.idempotencyRepository = &IdempotencyRepository{
+		psql: p,
+	}
+
+	return p.idempotencyRepository
+}
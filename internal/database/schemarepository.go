@@ -0,0 +1,125 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/models"
+)
+
+type SchemaRepository struct {
+	psql *PostgreSQL
+}
+
+func getSchemaForServiceNotFoundError(serviceName string) string {
+	return fmt.Sprintf("schema for service '%s' not found", serviceName)
+}
+
+func getSchemaVersionNotFoundError(serviceName string, version uint32) string {
+	return fmt.Sprintf("schema version '%d' for '%s' service not found", version, serviceName)
+}
+
+// Lock holds the schemas of the service for the rest of the transaction, so
+// that no other version is registered meanwhile.
+func (r *SchemaRepository) Lock(serviceName string) error {
+	return lockSchemas(r.psql.conn(), serviceName)
+}
+
+func lockSchemas(tx executor, serviceName string) error {
+	if row := tx.QueryRow("SELECT pg_advisory_xact_lock(hashtextextended($1, 2))",
+		serviceName,
+	); row.Err() != nil {
+		return row.Err()
+	}
+
+	return nil
+}
+
+func (r *SchemaRepository) Create(s *models.Schema) (*models.Schema, error) {
+	tx, err := r.psql.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = lockSchemas(tx, s.Service); err != nil {
+		return nil, err
+	}
+
+	if err = tx.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schemas WHERE service=$1",
+		s.Service,
+	).Scan(&s.Version); err != nil {
+		return nil, err
+	}
+	s.Version++
+
+	schemaData, err := json.Marshal(s.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if row := tx.QueryRow(
+		"INSERT INTO schemas (service, version, schema) VALUES ($1, $2, $3)",
+		s.Service,
+		s.Version,
+		schemaData,
+	); row.Err() != nil {
+		return nil, row.Err()
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (r *SchemaRepository) Read(s *models.Schema) (*models.Schema, error) {
+
+	if s.Version == 0 {
+		latest, err := r.Latest(s.Service)
+		if err != nil {
+			return nil, err
+		} else if latest == nil {
+			return nil, &NotFoundError{Resource: ResourceSchema, Name: s.Service, Msg: getSchemaForServiceNotFoundError(s.Service)}
+		}
+		return latest, nil
+	}
+
+	var schemaData []byte
+	if err := r.psql.conn().QueryRow("SELECT schema FROM schemas WHERE (service=$1) AND (version=$2)",
+		s.Service,
+		s.Version,
+	).Scan(&schemaData); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceSchemaVersion, Name: s.Service, Msg: getSchemaVersionNotFoundError(s.Service, s.Version)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(schemaData, &s.Keys); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Latest returns the latest schema registered for the service or nil if the
+// service has no schema.
+func (r *SchemaRepository) Latest(serviceName string) (*models.Schema, error) {
+	s := &models.Schema{Service: serviceName}
+
+	var schemaData []byte
+	if err := r.psql.conn().QueryRow("SELECT schema, version FROM schemas WHERE service=$1 ORDER BY version DESC LIMIT 1",
+		s.Service,
+	).Scan(&schemaData, &s.Version); err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(schemaData, &s.Keys); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
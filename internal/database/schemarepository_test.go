@@ -0,0 +1,194 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"regexp"
+	"testing"
+)
+
+func TestSchemaCreate(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &SchemaRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	type args struct {
+		s *models.Schema
+	}
+	type mockBehavior func(args args)
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		expects      *models.Schema
+		wantError    bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				s: &models.Schema{
+					Service: "test1",
+					Keys: map[string]models.SchemaKey{
+						"port": {Type: models.SchemaTypeInt, Required: true},
+					},
+				},
+			},
+			expects: &models.Schema{
+				Service: "test1",
+				Version: 3,
+				Keys: map[string]models.SchemaKey{
+					"port": {Type: models.SchemaTypeInt, Required: true},
+				},
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				query := regexp.QuoteMeta("SELECT pg_advisory_xact_lock(hashtextextended($1, 2))")
+				mock.ExpectQuery(query).
+					WithArgs(args.s.Service).WillReturnRows(&sqlmock.Rows{})
+
+				rows := mock.NewRows([]string{"max"}).AddRow(2)
+				query = regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0) FROM schemas WHERE service=$1")
+				mock.ExpectQuery(query).
+					WithArgs(args.s.Service).WillReturnRows(rows)
+
+				data, err := json.Marshal(args.s.Keys)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				query = regexp.QuoteMeta("INSERT INTO schemas (service, version, schema) VALUES ($1, $2, $3)")
+				mock.ExpectQuery(query).
+					WithArgs(args.s.Service, 3, data).WillReturnRows(&sqlmock.Rows{})
+
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.args)
+
+			got, err := r.Create(testCase.args.s)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+		})
+	}
+}
+
+func TestSchemaRead(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &SchemaRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	keys := map[string]models.SchemaKey{
+		"port": {Type: models.SchemaTypeInt, Required: true},
+	}
+	schemaData, err := json.Marshal(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		s *models.Schema
+	}
+	type mockBehavior func(args args)
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		expects      *models.Schema
+		wantError    bool
+	}{
+		{
+			name: "OK Last Record",
+			args: args{
+				s: &models.Schema{Service: "test1"},
+			},
+			expects: &models.Schema{Service: "test1", Version: 2, Keys: keys},
+			mockBehavior: func(args args) {
+				rows := mock.NewRows([]string{"schema", "version"}).AddRow(schemaData, 2)
+				query := regexp.QuoteMeta("SELECT schema, version FROM schemas WHERE service=$1 ORDER BY version DESC LIMIT 1")
+				mock.ExpectQuery(query).
+					WithArgs(args.s.Service).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "OK Specific Version",
+			args: args{
+				s: &models.Schema{Service: "test1", Version: 1},
+			},
+			expects: &models.Schema{Service: "test1", Version: 1, Keys: keys},
+			mockBehavior: func(args args) {
+				rows := mock.NewRows([]string{"schema"}).AddRow(schemaData)
+				query := regexp.QuoteMeta("SELECT schema FROM schemas WHERE (service=$1) AND (version=$2)")
+				mock.ExpectQuery(query).
+					WithArgs(args.s.Service, args.s.Version).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "SchemaForServiceNotFound",
+			args: args{
+				s: &models.Schema{Service: "dont-exist"},
+			},
+			wantError: true,
+			mockBehavior: func(args args) {
+				query := regexp.QuoteMeta("SELECT schema, version FROM schemas WHERE service=$1 ORDER BY version DESC LIMIT 1")
+				mock.ExpectQuery(query).
+					WithArgs(args.s.Service).WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name: "SchemaVersionNotFound",
+			args: args{
+				s: &models.Schema{Service: "test1", Version: 5},
+			},
+			wantError: true,
+			mockBehavior: func(args args) {
+				query := regexp.QuoteMeta("SELECT schema FROM schemas WHERE (service=$1) AND (version=$2)")
+				mock.ExpectQuery(query).
+					WithArgs(args.s.Service, args.s.Version).WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.args)
+
+			got, err := r.Read(testCase.args.s)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+		})
+	}
+}
@@ -3,12 +3,134 @@ CREATE DATABASE config_controller;
 \c config_controller;
 
 CREATE TABLE config_controller.public.configs (
-    id      SERIAL PRIMARY KEY,
-    service varchar(15) NOT NULL
+    id          SERIAL PRIMARY KEY,
+    service     text NOT NULL,
+    labels      JSON NOT NULL DEFAULT '{}',
+    annotations JSON NOT NULL DEFAULT '{}'
+);
+
+CREATE TABLE config_controller.public.changesets (
+    id          SERIAL PRIMARY KEY,
+    message     text NOT NULL DEFAULT '',
+    created_at  timestamp NOT NULL DEFAULT now()
 );
 
 CREATE TABLE config_controller.public.data_configs (
-    config_id   integer REFERENCES config_controller.public.configs (id),
-    version     integer,
-    data        JSON NOT NULL
-);
\ No newline at end of file
+    config_id    integer REFERENCES config_controller.public.configs (id),
+    version      integer,
+    data         JSON NOT NULL,
+    spec         JSON NOT NULL DEFAULT '{}',
+    created_at   timestamp NOT NULL DEFAULT now(),
+    message      text NOT NULL DEFAULT '',
+    rollback_of  integer,
+    changeset_id integer REFERENCES config_controller.public.changesets (id),
+    UNIQUE (config_id, version)
+);
+
+CREATE TABLE config_controller.public.schemas (
+    service     text NOT NULL,
+    version     integer NOT NULL,
+    schema      JSON NOT NULL,
+    PRIMARY KEY (service, version)
+);
+
+CREATE TABLE config_controller.public.templates (
+    name        varchar(63) NOT NULL,
+    version     integer NOT NULL,
+    template    JSON NOT NULL,
+    PRIMARY KEY (name, version)
+);
+
+CREATE TABLE config_controller.public.template_instances (
+    config_id           integer PRIMARY KEY REFERENCES config_controller.public.configs (id) ON DELETE CASCADE,
+    template            varchar(63) NOT NULL,
+    template_version    integer NOT NULL,
+    parameters          JSON NOT NULL
+);
+
+CREATE TABLE config_controller.public.tags (
+    config_id   integer REFERENCES config_controller.public.configs (id) ON DELETE CASCADE,
+    name        varchar(63) NOT NULL,
+    version     integer NOT NULL,
+    movable     boolean NOT NULL,
+    PRIMARY KEY (config_id, name)
+);
+
+CREATE TABLE config_controller.public.tag_history (
+    config_id   integer REFERENCES config_controller.public.configs (id) ON DELETE CASCADE,
+    name        varchar(63) NOT NULL,
+    old_version integer,
+    new_version integer NOT NULL,
+    moved_at    timestamp NOT NULL DEFAULT now()
+);
+
+CREATE TABLE config_controller.public.draft_configs (
+    id              SERIAL PRIMARY KEY,
+    config_id       integer REFERENCES config_controller.public.configs (id) ON DELETE CASCADE,
+    base_version    integer NOT NULL,
+    data            JSON NOT NULL,
+    spec            JSON NOT NULL DEFAULT '{}',
+    created_at      timestamp NOT NULL DEFAULT now()
+);
+
+CREATE TABLE config_controller.public.key_metadata (
+    config_id   integer REFERENCES config_controller.public.configs (id) ON DELETE CASCADE,
+    version     integer NOT NULL,
+    metadata    JSON NOT NULL,
+    created_at  timestamp NOT NULL DEFAULT now(),
+    PRIMARY KEY (config_id, version)
+);
+
+CREATE TABLE config_controller.public.idempotency_keys (
+    key          text PRIMARY KEY,
+    method       text NOT NULL,
+    request_hash text NOT NULL,
+    response     bytea NOT NULL,
+    created_at   timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idempotency_keys_created_at ON config_controller.public.idempotency_keys (created_at);
+
+CREATE FUNCTION config_controller.public.notify_config_change() RETURNS trigger AS $$
+DECLARE
+    change json;
+BEGIN
+    IF TG_TABLE_NAME = 'configs' THEN
+        change := json_build_object('service', OLD.service, 'version', 0, 'kind', 'deleted');
+    ELSE
+        change := json_build_object('service', (SELECT service FROM config_controller.public.configs WHERE id=NEW.config_id),
+            'version', NEW.version, 'kind', 'version');
+    END IF;
+
+    PERFORM pg_notify('config_changes', change::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- a statement deleting more than one version of a config deletes the config,
+-- which the configs trigger notifies once
+CREATE FUNCTION config_controller.public.notify_config_versions_deleted() RETURNS trigger AS $$
+DECLARE
+    change json;
+BEGIN
+    FOR change IN
+        SELECT json_build_object('service', c.service, 'version', max(d.version), 'kind', 'deleted')
+        FROM deleted_versions d JOIN config_controller.public.configs c ON c.id=d.config_id
+        GROUP BY c.service HAVING count(*) = 1
+    LOOP
+        PERFORM pg_notify('config_changes', change::text);
+    END LOOP;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER data_configs_notify AFTER INSERT ON config_controller.public.data_configs
+    FOR EACH ROW EXECUTE FUNCTION config_controller.public.notify_config_change();
+
+CREATE TRIGGER data_configs_notify_deleted AFTER DELETE ON config_controller.public.data_configs
+    REFERENCING OLD TABLE AS deleted_versions
+    FOR EACH STATEMENT EXECUTE FUNCTION config_controller.public.notify_config_versions_deleted();
+
+CREATE TRIGGER configs_notify AFTER DELETE ON config_controller.public.configs
+    FOR EACH ROW EXECUTE FUNCTION config_controller.public.notify_config_change();
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/wphylici/contest-cloud/internal/models"
+)
+
+type ServiceRepository struct {
+	psql *PostgreSQL
+}
+
+// List returns the services whose name starts with the prefix ordered by
+// name.
+func (r *ServiceRepository) List(prefix string) ([]*models.Service, error) {
+	rows, err := r.psql.conn().Query("SELECT c.id, c.service, c.labels, c.annotations, COALESCE(MAX(d.version), 0), MAX(d.created_at) "+
+		"FROM configs c LEFT JOIN data_configs d ON d.config_id=c.id WHERE left(c.service, length($1))=$1 GROUP BY c.id ORDER BY c.service",
+		prefix,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var services []*models.Service
+	for rows.Next() {
+		var labels, annotations []byte
+		var updatedAt sql.NullTime
+		s := &models.Service{}
+		if err = rows.Scan(&s.ID, &s.Name, &labels, &annotations, &s.LatestVersion, &updatedAt); err != nil {
+			return nil, err
+		}
+		s.UpdatedAt = updatedAt.Time
+		if err = json.Unmarshal(labels, &s.Labels); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(annotations, &s.Annotations); err != nil {
+			return nil, err
+		}
+		services = append(services, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+// SetLabels adds the labels and annotations of s to the service, replacing the
+// values of existing keys, and removes the listed keys. The config data is not
+// affected and no new config version is created.
+func (r *ServiceRepository) SetLabels(s *models.Service, removeLabels, removeAnnotations []string) (*models.Service, error) {
+	tx, err := r.psql.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var labels, annotations []byte
+	if err = tx.QueryRow("SELECT id, labels, annotations, "+
+		"(SELECT COALESCE(MAX(version), 0) FROM data_configs WHERE config_id=configs.id) FROM configs WHERE service=$1 FOR UPDATE",
+		s.Name,
+	).Scan(&s.ID, &labels, &annotations, &s.LatestVersion); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceConfig, Name: s.Name, Msg: getConfigForServiceNotFoundError(s.Name)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if labels, err = updateJSONMap(labels, s.Labels, removeLabels); err != nil {
+		return nil, err
+	}
+	if annotations, err = updateJSONMap(annotations, s.Annotations, removeAnnotations); err != nil {
+		return nil, err
+	}
+
+	if row := tx.QueryRow("UPDATE configs SET labels=$1, annotations=$2 WHERE id=$3",
+		labels,
+		annotations,
+		s.ID,
+	); row.Err() != nil {
+		return nil, row.Err()
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(labels, &s.Labels); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(annotations, &s.Annotations); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func updateJSONMap(data []byte, set map[string]string, remove []string) ([]byte, error) {
+	m := map[string]string{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	for k, v := range set {
+		m[k] = v
+	}
+	for _, k := range remove {
+		delete(m, k)
+	}
+
+	return json.Marshal(m)
+}
+
+// Usage returns the storage consumed by every namespace, or only by the given
+// namespace, ordered by namespace.
+func (r *ServiceRepository) Usage(namespace string) ([]*models.Usage, error) {
+	rows, err := r.psql.conn().Query("SELECT split_part(c.service, '/', 1) AS namespace, COUNT(DISTINCT c.id), COUNT(d.version), "+
+		"COALESCE(SUM(octet_length(d.data::text) + octet_length(d.spec::text)), 0) "+
+		"FROM configs c LEFT JOIN data_configs d ON d.config_id=c.id "+
+		"WHERE ($1='' OR split_part(c.service, '/', 1)=$1) GROUP BY namespace ORDER BY namespace",
+		namespace,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usage []*models.Usage
+	for rows.Next() {
+		u := &models.Usage{}
+		if err = rows.Scan(&u.Namespace, &u.Services, &u.Versions, &u.Bytes); err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// LockNamespace holds the namespace for the rest of the transaction, so that
+// its usage doesn't change between a quota check and the write it allows.
+func (r *ServiceRepository) LockNamespace(namespace string) error {
+	if row := r.psql.conn().QueryRow("SELECT pg_advisory_xact_lock(hashtextextended($1, 1))",
+		namespace,
+	); row.Err() != nil {
+		return row.Err()
+	}
+
+	return nil
+}
+
+// VersionCount returns the number of stored versions of the service config.
+func (r *ServiceRepository) VersionCount(serviceName string) (int, error) {
+	var count int
+	if err := r.psql.conn().QueryRow("SELECT COUNT(d.version) FROM configs c JOIN data_configs d ON d.config_id=c.id WHERE c.service=$1",
+		serviceName,
+	).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,230 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestServiceList(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &ServiceRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	updatedAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	rows := mock.NewRows([]string{"id", "service", "labels", "annotations", "version", "updated_at"}).
+		AddRow(1, "test1", []byte(`{"team":"payments"}`), []byte(`{}`), 3, updatedAt).
+		AddRow(2, "test2", []byte(`{}`), []byte(`{"note":"legacy"}`), 0, nil)
+	query := regexp.QuoteMeta("SELECT c.id, c.service, c.labels, c.annotations, COALESCE(MAX(d.version), 0), MAX(d.created_at) " +
+		"FROM configs c LEFT JOIN data_configs d ON d.config_id=c.id WHERE left(c.service, length($1))=$1 GROUP BY c.id ORDER BY c.service")
+	mock.ExpectQuery(query).WithArgs("test").WillReturnRows(rows)
+
+	got, err := r.List("test")
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.Service{
+		{ID: 1, Name: "test1", Labels: map[string]string{"team": "payments"}, Annotations: map[string]string{}, LatestVersion: 3, UpdatedAt: updatedAt},
+		{ID: 2, Name: "test2", Labels: map[string]string{}, Annotations: map[string]string{"note": "legacy"}},
+	}, got)
+}
+
+func TestServiceSetLabels(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &ServiceRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	type args struct {
+		s                 *models.Service
+		removeLabels      []string
+		removeAnnotations []string
+	}
+	type mockBehavior func(args args)
+
+	selectQuery := regexp.QuoteMeta("SELECT id, labels, annotations, " +
+		"(SELECT COALESCE(MAX(version), 0) FROM data_configs WHERE config_id=configs.id) FROM configs WHERE service=$1 FOR UPDATE")
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		expects      *models.Service
+		wantError    bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				s: &models.Service{
+					Name:   "test1",
+					Labels: map[string]string{"tier": "api"},
+				},
+				removeLabels:      []string{"owner"},
+				removeAnnotations: []string{"note"},
+			},
+			expects: &models.Service{
+				ID:            1,
+				Name:          "test1",
+				Labels:        map[string]string{"team": "payments", "tier": "api"},
+				Annotations:   map[string]string{},
+				LatestVersion: 2,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := mock.NewRows([]string{"id", "labels", "annotations", "version"}).
+					AddRow(1, []byte(`{"team":"payments","owner":"bob"}`), []byte(`{"note":"legacy"}`), 2)
+				mock.ExpectQuery(selectQuery).
+					WithArgs(args.s.Name).WillReturnRows(rows)
+
+				query := regexp.QuoteMeta("UPDATE configs SET labels=$1, annotations=$2 WHERE id=$3")
+				mock.ExpectQuery(query).
+					WithArgs([]byte(`{"team":"payments","tier":"api"}`), []byte(`{}`), 1).WillReturnRows(&sqlmock.Rows{})
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ConfigForServiceNotFound",
+			args: args{
+				s: &models.Service{Name: "dont-exist"},
+			},
+			wantError: true,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(selectQuery).
+					WithArgs(args.s.Name).WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.args)
+
+			got, err := r.SetLabels(testCase.args.s, testCase.args.removeLabels, testCase.args.removeAnnotations)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestServiceUsage(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &ServiceRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	query := regexp.QuoteMeta("SELECT split_part(c.service, '/', 1) AS namespace, COUNT(DISTINCT c.id), COUNT(d.version), " +
+		"COALESCE(SUM(octet_length(d.data::text) + octet_length(d.spec::text)), 0) " +
+		"FROM configs c LEFT JOIN data_configs d ON d.config_id=c.id " +
+		"WHERE ($1='' OR split_part(c.service, '/', 1)=$1) GROUP BY namespace ORDER BY namespace")
+
+	testTable := []struct {
+		name         string
+		namespace    string
+		mockBehavior func(namespace string)
+		expects      []*models.Usage
+		wantError    bool
+	}{
+		{
+			name:      "OK All Namespaces",
+			namespace: "",
+			expects: []*models.Usage{
+				{Namespace: "payments", Services: 2, Versions: 5, Bytes: 1200},
+				{Namespace: "search", Services: 1, Versions: 1, Bytes: 80},
+			},
+			mockBehavior: func(namespace string) {
+				rows := mock.NewRows([]string{"namespace", "services", "versions", "bytes"}).
+					AddRow("payments", 2, 5, 1200).
+					AddRow("search", 1, 1, 80)
+				mock.ExpectQuery(query).WithArgs(namespace).WillReturnRows(rows)
+			},
+		},
+		{
+			name:      "OK Unknown Namespace",
+			namespace: "billing",
+			mockBehavior: func(namespace string) {
+				rows := mock.NewRows([]string{"namespace", "services", "versions", "bytes"})
+				mock.ExpectQuery(query).WithArgs(namespace).WillReturnRows(rows)
+			},
+		},
+		{
+			name:      "Error",
+			namespace: "payments",
+			wantError: true,
+			mockBehavior: func(namespace string) {
+				mock.ExpectQuery(query).WithArgs(namespace).WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.namespace)
+
+			got, err := r.Usage(testCase.namespace)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestServiceLockNamespace(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	mock.ExpectBegin()
+	query := regexp.QuoteMeta("SELECT pg_advisory_xact_lock(hashtextextended($1, 1))")
+	mock.ExpectQuery(query).WithArgs("payments").WillReturnRows(&sqlmock.Rows{})
+	mock.ExpectCommit()
+
+	ctx := NewContext(context.Background(), &PostgreSQL{db: dbmock})
+	err = WithTransaction(ctx, func(ctx context.Context) error {
+		return FromContext(ctx).Service().LockNamespace("payments")
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
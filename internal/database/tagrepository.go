@@ -0,0 +1,231 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/models"
+)
+
+type TagRepository struct {
+	psql *PostgreSQL
+}
+
+func getTagNotFoundError(serviceName string, tagName string) string {
+	return fmt.Sprintf("tag '%s' for '%s' service not found", tagName, serviceName)
+}
+
+func getTagAlreadyExistsError(serviceName string, tagName string) string {
+	return fmt.Sprintf("tag '%s' for '%s' service already exists", tagName, serviceName)
+}
+
+func getTagIsImmutableError(serviceName string, tagName string) string {
+	return fmt.Sprintf("tag '%s' for '%s' service is immutable", tagName, serviceName)
+}
+
+func (r *TagRepository) Create(t *models.Tag) (*models.Tag, error) {
+	tx, err := r.psql.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	configID, err := r.resolveVersion(tx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	var isTagExist bool
+	if err = tx.QueryRow("SELECT EXISTS(SELECT name FROM tags WHERE (config_id=$1) AND (name=$2))",
+		configID,
+		t.Name,
+	).Scan(&isTagExist); err != nil {
+		return nil, err
+	} else if isTagExist {
+		return nil, &AlreadyExistsError{Resource: ResourceTag, Name: t.Name, Msg: getTagAlreadyExistsError(t.Service, t.Name)}
+	}
+
+	if row := tx.QueryRow("INSERT INTO tags (config_id, name, version, movable) VALUES ($1, $2, $3, $4)",
+		configID,
+		t.Name,
+		t.Version,
+		t.Movable,
+	); row.Err() != nil {
+		return nil, row.Err()
+	}
+
+	if row := tx.QueryRow("INSERT INTO tag_history (config_id, name, new_version) VALUES ($1, $2, $3)",
+		configID,
+		t.Name,
+		t.Version,
+	); row.Err() != nil {
+		return nil, row.Err()
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *TagRepository) Move(t *models.Tag) (*models.Tag, error) {
+	tx, err := r.psql.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = r.move(tx, t); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// move repoints the movable tag to t.Version and records the move in the tag
+// history.
+func (r *TagRepository) move(tx Tx, t *models.Tag) error {
+	configID, err := r.resolveVersion(tx, t)
+	if err != nil {
+		return err
+	}
+
+	var oldVersion uint32
+	if err = tx.QueryRow("SELECT version, movable FROM tags WHERE (config_id=$1) AND (name=$2) FOR UPDATE",
+		configID,
+		t.Name,
+	).Scan(&oldVersion, &t.Movable); err == sql.ErrNoRows {
+		return &NotFoundError{Resource: ResourceTag, Name: t.Name, Msg: getTagNotFoundError(t.Service, t.Name)}
+	} else if err != nil {
+		return err
+	} else if !t.Movable {
+		return &PreconditionError{Type: PreconditionTagImmutable, Subject: t.Name, Msg: getTagIsImmutableError(t.Service, t.Name)}
+	}
+
+	if row := tx.QueryRow("UPDATE tags SET version=$1 WHERE (config_id=$2) AND (name=$3)",
+		t.Version,
+		configID,
+		t.Name,
+	); row.Err() != nil {
+		return row.Err()
+	}
+
+	if row := tx.QueryRow("INSERT INTO tag_history (config_id, name, old_version, new_version) VALUES ($1, $2, $3, $4)",
+		configID,
+		t.Name,
+		oldVersion,
+		t.Version,
+	); row.Err() != nil {
+		return row.Err()
+	}
+
+	return nil
+}
+
+// resolveVersion returns the config ID of the tagged service and checks that
+// the tagged version exists, a zero version is resolved to the latest one.
+func (r *TagRepository) resolveVersion(tx Tx, t *models.Tag) (int, error) {
+	var configID int
+
+	if err := tx.QueryRow("SELECT id FROM configs WHERE service=$1",
+		t.Service,
+	).Scan(&configID); err == sql.ErrNoRows {
+		return 0, &NotFoundError{Resource: ResourceConfig, Name: t.Service, Msg: getConfigForServiceNotFoundError(t.Service)}
+	} else if err != nil {
+		return 0, err
+	}
+
+	if t.Version == 0 {
+		if err := tx.QueryRow("SELECT version FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1",
+			configID,
+		).Scan(&t.Version); err != nil {
+			return 0, err
+		}
+		return configID, nil
+	}
+
+	var isVersionExist bool
+	if err := tx.QueryRow("SELECT EXISTS(SELECT version FROM data_configs WHERE (config_id=$1) AND (version=$2))",
+		configID,
+		t.Version,
+	).Scan(&isVersionExist); err != nil {
+		return 0, err
+	} else if !isVersionExist {
+		return 0, &NotFoundError{Resource: ResourceConfigVersion, Name: t.Service, Msg: getConfigVersionNotFoundError(t.Service, t.Version)}
+	}
+
+	return configID, nil
+}
+
+func (r *TagRepository) Read(t *models.Tag) (*models.Tag, error) {
+
+	if err := r.psql.conn().QueryRow("SELECT t.version, t.movable FROM tags t JOIN configs c ON c.id = t.config_id "+
+		"WHERE (c.service=$1) AND (t.name=$2)",
+		t.Service,
+		t.Name,
+	).Scan(&t.Version, &t.Movable); err == sql.ErrNoRows {
+		return nil, &NotFoundError{Resource: ResourceTag, Name: t.Name, Msg: getTagNotFoundError(t.Service, t.Name)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *TagRepository) List(serviceName string) ([]*models.Tag, error) {
+	rows, err := r.psql.conn().Query("SELECT t.name, t.version, t.movable FROM tags t JOIN configs c ON c.id = t.config_id "+
+		"WHERE c.service=$1 ORDER BY t.name",
+		serviceName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*models.Tag
+	for rows.Next() {
+		t := &models.Tag{
+			Service: serviceName,
+		}
+		if err = rows.Scan(&t.Name, &t.Version, &t.Movable); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *TagRepository) History(serviceName string) ([]*models.TagMove, error) {
+	rows, err := r.psql.conn().Query("SELECT h.name, COALESCE(h.old_version, 0), h.new_version, h.moved_at FROM tag_history h "+
+		"JOIN configs c ON c.id = h.config_id WHERE c.service=$1 ORDER BY h.moved_at, h.name",
+		serviceName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var moves []*models.TagMove
+	for rows.Next() {
+		m := &models.TagMove{}
+		if err = rows.Scan(&m.Name, &m.OldVersion, &m.NewVersion, &m.MovedAt); err != nil {
+			return nil, err
+		}
+		moves = append(moves, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return moves, nil
+}
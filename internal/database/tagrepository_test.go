@@ -0,0 +1,254 @@
+package database
+
+import (
+	"database/sql"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"regexp"
+	"testing"
+)
+
+func TestTagCreate(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &TagRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	type args struct {
+		t *models.Tag
+	}
+	type mockBehavior func(args args)
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		expects      *models.Tag
+		wantError    bool
+	}{
+		{
+			name: "OK Latest Version",
+			args: args{
+				t: &models.Tag{Service: "test1", Name: "stable"},
+			},
+			expects: &models.Tag{Service: "test1", Name: "stable", Version: 3},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1")
+				mock.ExpectQuery(query).
+					WithArgs(args.t.Service).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"version"}).AddRow(3)
+				query = regexp.QuoteMeta("SELECT version FROM data_configs WHERE config_id=$1 ORDER BY version DESC LIMIT 1")
+				mock.ExpectQuery(query).
+					WithArgs(1).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"exist"}).AddRow(false)
+				query = regexp.QuoteMeta("SELECT EXISTS(SELECT name FROM tags WHERE (config_id=$1) AND (name=$2))")
+				mock.ExpectQuery(query).
+					WithArgs(1, args.t.Name).WillReturnRows(rows)
+
+				query = regexp.QuoteMeta("INSERT INTO tags (config_id, name, version, movable) VALUES ($1, $2, $3, $4)")
+				mock.ExpectQuery(query).
+					WithArgs(1, args.t.Name, 3, false).WillReturnRows(&sqlmock.Rows{})
+
+				query = regexp.QuoteMeta("INSERT INTO tag_history (config_id, name, new_version) VALUES ($1, $2, $3)")
+				mock.ExpectQuery(query).
+					WithArgs(1, args.t.Name, 3).WillReturnRows(&sqlmock.Rows{})
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ConfigVersionNotFound",
+			args: args{
+				t: &models.Tag{Service: "test1", Name: "stable", Version: 5},
+			},
+			wantError: true,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1")
+				mock.ExpectQuery(query).
+					WithArgs(args.t.Service).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"exist"}).AddRow(false)
+				query = regexp.QuoteMeta("SELECT EXISTS(SELECT version FROM data_configs WHERE (config_id=$1) AND (version=$2))")
+				mock.ExpectQuery(query).
+					WithArgs(1, args.t.Version).WillReturnRows(rows)
+
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "TagAlreadyExists",
+			args: args{
+				t: &models.Tag{Service: "test1", Name: "stable", Version: 2},
+			},
+			wantError: true,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+				query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1")
+				mock.ExpectQuery(query).
+					WithArgs(args.t.Service).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"exist"}).AddRow(true)
+				query = regexp.QuoteMeta("SELECT EXISTS(SELECT version FROM data_configs WHERE (config_id=$1) AND (version=$2))")
+				mock.ExpectQuery(query).
+					WithArgs(1, args.t.Version).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"exist"}).AddRow(true)
+				query = regexp.QuoteMeta("SELECT EXISTS(SELECT name FROM tags WHERE (config_id=$1) AND (name=$2))")
+				mock.ExpectQuery(query).
+					WithArgs(1, args.t.Name).WillReturnRows(rows)
+
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.args)
+
+			got, err := r.Create(testCase.args.t)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTagMove(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &TagRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	type args struct {
+		t *models.Tag
+	}
+	type mockBehavior func(args args)
+
+	expectVersion := func(args args) {
+		rows := mock.NewRows([]string{"id"}).AddRow(1)
+		query := regexp.QuoteMeta("SELECT id FROM configs WHERE service=$1")
+		mock.ExpectQuery(query).
+			WithArgs(args.t.Service).WillReturnRows(rows)
+
+		rows = mock.NewRows([]string{"exist"}).AddRow(true)
+		query = regexp.QuoteMeta("SELECT EXISTS(SELECT version FROM data_configs WHERE (config_id=$1) AND (version=$2))")
+		mock.ExpectQuery(query).
+			WithArgs(1, args.t.Version).WillReturnRows(rows)
+	}
+	selectTagQuery := regexp.QuoteMeta("SELECT version, movable FROM tags WHERE (config_id=$1) AND (name=$2) FOR UPDATE")
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		expects      *models.Tag
+		wantError    bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				t: &models.Tag{Service: "test1", Name: "prod", Version: 4},
+			},
+			expects: &models.Tag{Service: "test1", Name: "prod", Version: 4, Movable: true},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectVersion(args)
+
+				rows := mock.NewRows([]string{"version", "movable"}).AddRow(2, true)
+				mock.ExpectQuery(selectTagQuery).
+					WithArgs(1, args.t.Name).WillReturnRows(rows)
+
+				query := regexp.QuoteMeta("UPDATE tags SET version=$1 WHERE (config_id=$2) AND (name=$3)")
+				mock.ExpectQuery(query).
+					WithArgs(args.t.Version, 1, args.t.Name).WillReturnRows(&sqlmock.Rows{})
+
+				query = regexp.QuoteMeta("INSERT INTO tag_history (config_id, name, old_version, new_version) VALUES ($1, $2, $3, $4)")
+				mock.ExpectQuery(query).
+					WithArgs(1, args.t.Name, 2, args.t.Version).WillReturnRows(&sqlmock.Rows{})
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "TagIsImmutable",
+			args: args{
+				t: &models.Tag{Service: "test1", Name: "stable", Version: 4},
+			},
+			wantError: true,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectVersion(args)
+
+				rows := mock.NewRows([]string{"version", "movable"}).AddRow(2, false)
+				mock.ExpectQuery(selectTagQuery).
+					WithArgs(1, args.t.Name).WillReturnRows(rows)
+
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "TagNotFound",
+			args: args{
+				t: &models.Tag{Service: "test1", Name: "dont-exist", Version: 4},
+			},
+			wantError: true,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectVersion(args)
+
+				mock.ExpectQuery(selectTagQuery).
+					WithArgs(1, args.t.Name).WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.args)
+
+			got, err := r.Move(testCase.args.t)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/models"
+)
+
+type TemplateRepository struct {
+	psql *PostgreSQL
+}
+
+func getTemplateNotFoundError(templateName string) string {
+	return fmt.Sprintf("template '%s' not found", templateName)
+}
+
+func getTemplateVersionNotFoundError(templateName string, version uint32) string {
+	return fmt.Sprintf("version '%d' of template '%s' not found", version, templateName)
+}
+
+func (r *TemplateRepository) Create(t *models.Template) (*models.Template, error) {
+	tx, err := r.psql.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow("SELECT COALESCE(MAX(version), 0) FROM templates WHERE name=$1",
+		t.Name,
+	).Scan(&t.Version); err != nil {
+		return nil, err
+	}
+	t.Version++
+
+	templateData, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if row := tx.QueryRow(
+		"INSERT INTO templates (name, version, template) VALUES ($1, $2, $3)",
+		t.Name,
+		t.Version,
+		templateData,
+	); row.Err() != nil {
+		return nil, row.Err()
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *TemplateRepository) Read(t *models.Template) (*models.Template, error) {
+	var templateData []byte
+
+	if t.Version == 0 {
+		if err := r.psql.conn().QueryRow("SELECT template, version FROM templates WHERE name=$1 ORDER BY version DESC LIMIT 1",
+			t.Name,
+		).Scan(&templateData, &t.Version); err == sql.ErrNoRows {
+			return nil, &NotFoundError{Resource: ResourceTemplate, Name: t.Name, Msg: getTemplateNotFoundError(t.Name)}
+		} else if err != nil {
+			return nil, err
+		}
+	} else {
+		if err := r.psql.conn().QueryRow("SELECT template FROM templates WHERE (name=$1) AND (version=$2)",
+			t.Name,
+			t.Version,
+		).Scan(&templateData); err == sql.ErrNoRows {
+			return nil, &NotFoundError{Resource: ResourceTemplateVersion, Name: t.Name, Msg: getTemplateVersionNotFoundError(t.Name, t.Version)}
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := json.Unmarshal(templateData, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// CreateInstance creates the service config instantiated from a template and
+// records which template version and parameters it was created from.
+func (r *TemplateRepository) CreateInstance(c *models.ServiceConfig, i *models.TemplateInstance) (*models.ServiceConfig, error) {
+	tx, err := r.psql.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = r.psql.ServiceConfig().create(tx, c); err != nil {
+		return nil, err
+	}
+
+	parameters, err := json.Marshal(i.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if row := tx.QueryRow(
+		"INSERT INTO template_instances (config_id, template, template_version, parameters) VALUES ($1, $2, $3, $4)",
+		c.ID,
+		i.Template,
+		i.TemplateVersion,
+		parameters,
+	); row.Err() != nil {
+		return nil, row.Err()
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (r *TemplateRepository) Instances(templateName string) ([]*models.TemplateInstance, error) {
+	rows, err := r.psql.conn().Query("SELECT c.service, i.template_version, i.parameters FROM template_instances i "+
+		"JOIN configs c ON c.id = i.config_id WHERE i.template=$1 ORDER BY c.service",
+		templateName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instances []*models.TemplateInstance
+	for rows.Next() {
+		var parameters []byte
+		i := &models.TemplateInstance{
+			Template: templateName,
+		}
+		if err = rows.Scan(&i.Service, &i.TemplateVersion, &parameters); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(parameters, &i.Parameters); err != nil {
+			return nil, err
+		}
+		instances = append(instances, i)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"encoding/json"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"regexp"
+	"testing"
+)
+
+func TestTemplateCreateInstance(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &TemplateRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	type args struct {
+		sc *models.ServiceConfig
+		i  *models.TemplateInstance
+	}
+	type mockBehavior func(args args)
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		expectsv     *models.ServiceConfig
+		wantError    bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				sc: &models.ServiceConfig{
+					Service: "test1",
+					Data:    map[string]string{"owner": "payments"},
+				},
+				i: &models.TemplateInstance{
+					Service:         "test1",
+					Template:        "go-service",
+					TemplateVersion: 2,
+					Parameters:      map[string]string{"team": "payments"},
+				},
+			},
+			expectsv: &models.ServiceConfig{
+				ID:      1,
+				Service: "test1",
+				Data:    map[string]string{"owner": "payments"},
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := mock.NewRows([]string{"exist"}).AddRow(false)
+				query := regexp.QuoteMeta("SELECT EXISTS(SELECT service FROM configs WHERE service=$1)")
+				mock.ExpectQuery(query).
+					WithArgs(args.sc.Service).WillReturnRows(rows)
+
+				rows = mock.NewRows([]string{"id"}).AddRow(1)
+				query = regexp.QuoteMeta("INSERT INTO configs (service) VALUES ($1) RETURNING id")
+				mock.ExpectQuery(query).
+					WithArgs(args.sc.Service).WillReturnRows(rows)
+
+				data, err := json.Marshal(args.sc.Data)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				query = regexp.QuoteMeta("INSERT INTO data_configs (config_id, version, data, spec) VALUES ($1, $2, $3, $4)")
+				mock.ExpectQuery(query).
+					WithArgs(1, 1, data, []byte("{}")).WillReturnRows(&sqlmock.Rows{})
+
+				parameters, err := json.Marshal(args.i.Parameters)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				query = regexp.QuoteMeta("INSERT INTO template_instances (config_id, template, template_version, parameters) VALUES ($1, $2, $3, $4)")
+				mock.ExpectQuery(query).
+					WithArgs(1, args.i.Template, args.i.TemplateVersion, parameters).WillReturnRows(&sqlmock.Rows{})
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ConfigAlreadyBeenCreatedError",
+			args: args{
+				sc: &models.ServiceConfig{
+					Service: "test1",
+					Data:    map[string]string{"owner": "payments"},
+				},
+				i: &models.TemplateInstance{
+					Service:         "test1",
+					Template:        "go-service",
+					TemplateVersion: 2,
+				},
+			},
+			wantError: true,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := mock.NewRows([]string{"exist"}).AddRow(true)
+				query := regexp.QuoteMeta("SELECT EXISTS(SELECT service FROM configs WHERE service=$1)")
+				mock.ExpectQuery(query).
+					WithArgs(args.sc.Service).WillReturnRows(rows)
+
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.args)
+
+			got, err := r.CreateInstance(testCase.args.sc, testCase.args.i)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expectsv, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTemplateInstances(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	r := &TemplateRepository{
+		psql: &PostgreSQL{
+			db: dbmock,
+		},
+	}
+
+	rows := mock.NewRows([]string{"service", "template_version", "parameters"}).
+		AddRow("test1", 1, []byte(`{"team":"payments"}`)).
+		AddRow("test2", 2, []byte(`{}`))
+	query := regexp.QuoteMeta("SELECT c.service, i.template_version, i.parameters FROM template_instances i " +
+		"JOIN configs c ON c.id = i.config_id WHERE i.template=$1 ORDER BY c.service")
+	mock.ExpectQuery(query).
+		WithArgs("go-service").WillReturnRows(rows)
+
+	got, err := r.Instances("go-service")
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.TemplateInstance{
+		{Service: "test1", Template: "go-service", TemplateVersion: 1, Parameters: map[string]string{"team": "payments"}},
+		{Service: "test2", Template: "go-service", TemplateVersion: 2, Parameters: map[string]string{}},
+	}, got)
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Tx is a transaction begun by a repository, it is a savepoint when the
+// repository runs inside a request transaction.
+type Tx interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	Commit() error
+	Rollback() error
+}
+
+type executor interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+type savepoint struct {
+	*sql.Tx
+	name string
+	done bool
+}
+
+func (s *savepoint) Commit() error {
+	if s.done {
+		return sql.ErrTxDone
+	}
+	s.done = true
+
+	_, err := s.Tx.Exec("RELEASE SAVEPOINT " + s.name)
+	return err
+}
+
+func (s *savepoint) Rollback() error {
+	if s.done {
+		return sql.ErrTxDone
+	}
+	s.done = true
+
+	_, err := s.Tx.Exec("ROLLBACK TO SAVEPOINT " + s.name)
+	return err
+}
+
+type contextKey struct{}
+
+// Begin returns a copy of p whose repositories run in a single transaction,
+// the transactions they begin become savepoints of it. The transaction is
+// rolled back when ctx is done.
+func (p *PostgreSQL) Begin(ctx context.Context) (*PostgreSQL, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgreSQL{
+		config: p.config,
+		db:     p.db,
+		tx:     tx,
+	}, nil
+}
+
+func (p *PostgreSQL) Commit() error {
+	return p.tx.Commit()
+}
+
+func (p *PostgreSQL) Rollback() error {
+	return p.tx.Rollback()
+}
+
+func (p *PostgreSQL) begin() (Tx, error) {
+	if p.tx == nil {
+		return p.db.Begin()
+	}
+
+	p.savepoints++
+	sp := &savepoint{Tx: p.tx, name: fmt.Sprintf("sp_%d", p.savepoints)}
+	if _, err := p.tx.Exec("SAVEPOINT " + sp.name); err != nil {
+		return nil, err
+	}
+
+	return sp, nil
+}
+
+func (p *PostgreSQL) conn() executor {
+	if p.tx == nil {
+		return p.db
+	}
+	return p.tx
+}
+
+// WithTransaction runs fn with a context carrying a transaction, the one of ctx
+// if there is one or a new one that is committed when fn succeeds.
+func WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	psql := FromContext(ctx)
+	if psql.tx != nil {
+		return fn(ctx)
+	}
+
+	psql, err := psql.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer psql.Rollback()
+
+	if err = fn(NewContext(ctx, psql)); err != nil {
+		return err
+	}
+
+	return psql.Commit()
+}
+
+// NewContext returns a context carrying psql, usually one returned by Begin.
+func NewContext(ctx context.Context, psql *PostgreSQL) context.Context {
+	return context.WithValue(ctx, contextKey{}, psql)
+}
+
+// FromContext returns the PostgreSQL carried by ctx or Psql.
+func FromContext(ctx context.Context) *PostgreSQL {
+	if psql, ok := ctx.Value(contextKey{}).(*PostgreSQL); ok {
+		return psql
+	}
+	return Psql
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"errors"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestSavepoint(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	testTable := []struct {
+		name         string
+		mockBehavior func()
+		end          func(tx Tx) error
+	}{
+		{
+			name: "Commit",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT sp_2")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT sp_2")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			end: func(tx Tx) error {
+				return tx.Commit()
+			},
+		},
+		{
+			name: "Rollback",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("ROLLBACK TO SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT sp_2")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("ROLLBACK TO SAVEPOINT sp_2")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			end: func(tx Tx) error {
+				return tx.Rollback()
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			psql, err := NewFromDB(dbmock).Begin(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// every transaction a repository begins is a savepoint of its own
+			for i := 0; i < 2; i++ {
+				tx, err := psql.begin()
+				assert.NoError(t, err)
+				assert.NoError(t, testCase.end(tx))
+				assert.Error(t, tx.Commit())
+				assert.Error(t, tx.Rollback())
+			}
+
+			assert.NoError(t, psql.Commit())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWithTransaction(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	errFailed := errors.New("failed")
+
+	testTable := []struct {
+		name         string
+		mockBehavior func()
+		fn           func(ctx context.Context) error
+		wantError    error
+	}{
+		{
+			name: "Commit",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context) error {
+				return nil
+			},
+		},
+		{
+			name: "Rollback",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fn: func(ctx context.Context) error {
+				return errFailed
+			},
+			wantError: errFailed,
+		},
+		{
+			name: "Nested",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context) error {
+				psql := FromContext(ctx)
+				return WithTransaction(ctx, func(ctx context.Context) error {
+					if FromContext(ctx) != psql {
+						return errors.New("nested transaction begun")
+					}
+					return nil
+				})
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			ctx := NewContext(context.Background(), NewFromDB(dbmock))
+			assert.Equal(t, testCase.wantError, WithTransaction(ctx, testCase.fn))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package formats
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var dotenvKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// parseDotenv parses a dotenv file of KEY=value lines. Lines may start with
+// "export", double quoted values support escapes and may span several lines,
+// single quoted values are taken literally and unquoted values end at an
+// inline comment.
+func parseDotenv(document []byte) ([]byte, error) {
+	d := newFlatData(Dotenv)
+	lines := strings.Split(strings.ReplaceAll(string(document), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := lines[i]
+
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+		pos := len(line) - len(trimmed)
+
+		if strings.HasPrefix(trimmed, "export ") || strings.HasPrefix(trimmed, "export\t") {
+			rest := strings.TrimLeft(trimmed[len("export"):], " \t")
+			pos += len(trimmed) - len(rest)
+			trimmed = rest
+		}
+
+		eq := strings.IndexByte(trimmed, '=')
+		if eq == -1 {
+			return nil, &ParseError{Format: Dotenv, Line: lineNumber, Column: pos + len(strings.TrimRight(trimmed, " \t")) + 1, Msg: "expected '='"}
+		}
+
+		key := strings.TrimRight(trimmed[:eq], " \t")
+		if !dotenvKeyPattern.MatchString(key) {
+			return nil, &ParseError{Format: Dotenv, Line: lineNumber, Column: pos + 1, Msg: fmt.Sprintf("invalid key '%s'", key)}
+		}
+		keyColumn := pos + 1
+
+		pos += eq + 1
+		rest := line[pos:]
+		value := strings.TrimLeft(rest, " \t")
+		pos += len(rest) - len(value)
+
+		var err error
+		switch {
+		case strings.HasPrefix(value, `"`):
+			value, i, err = dotenvDoubleQuoted(lines, i, pos)
+		case strings.HasPrefix(value, "'"):
+			value, err = dotenvSingleQuoted(line, lineNumber, pos)
+		default:
+			if n := strings.Index(value, " #"); n != -1 {
+				value = value[:n]
+			} else if n = strings.Index(value, "\t#"); n != -1 {
+				value = value[:n]
+			}
+			value = strings.TrimRight(value, " \t")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err = d.set(key, value, lineNumber, keyColumn); err != nil {
+			return nil, err
+		}
+	}
+
+	return d.document(nil)
+}
+
+// dotenvDoubleQuoted reads the double quoted value opening at pos of the
+// line, it returns the value and the index of the line where it closes.
+func dotenvDoubleQuoted(lines []string, i int, pos int) (string, int, error) {
+	var b strings.Builder
+	openLine, openColumn := i+1, pos+1
+
+	line := lines[i][pos+1:]
+	column := pos + 2
+	for {
+		for n := 0; n < len(line); n++ {
+			switch line[n] {
+			case '"':
+				if err := dotenvTrailing(line[n+1:], i+1, column+n+1); err != nil {
+					return "", 0, err
+				}
+				return b.String(), i, nil
+			case '\\':
+				if n+1 == len(line) {
+					b.WriteByte('\\')
+					continue
+				}
+				n++
+				switch line[n] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				case 'r':
+					b.WriteByte('\r')
+				case '"', '\\', '$':
+					b.WriteByte(line[n])
+				default:
+					b.WriteByte('\\')
+					b.WriteByte(line[n])
+				}
+			default:
+				b.WriteByte(line[n])
+			}
+		}
+
+		if i+1 == len(lines) {
+			return "", 0, &ParseError{Format: Dotenv, Line: openLine, Column: openColumn, Msg: "unterminated double quoted value"}
+		}
+		b.WriteByte('\n')
+		i++
+		line, column = lines[i], 1
+	}
+}
+
+func dotenvSingleQuoted(line string, lineNumber int, pos int) (string, error) {
+	end := strings.IndexByte(line[pos+1:], '\'')
+	if end == -1 {
+		return "", &ParseError{Format: Dotenv, Line: lineNumber, Column: pos + 1, Msg: "unterminated single quoted value"}
+	}
+
+	closing := pos + 1 + end
+	if err := dotenvTrailing(line[closing+1:], lineNumber, closing+2); err != nil {
+		return "", err
+	}
+
+	return line[pos+1 : closing], nil
+}
+
+// dotenvTrailing checks that only whitespace or a comment follows a quoted
+// value.
+func dotenvTrailing(rest string, lineNumber int, column int) error {
+	trimmed := strings.TrimLeft(rest, " \t")
+	if trimmed == "" || trimmed[0] == '#' {
+		return nil
+	}
+
+	return &ParseError{
+		Format: Dotenv,
+		Line:   lineNumber,
+		Column: column + len(rest) - len(trimmed),
+		Msg:    "unexpected characters after quoted value",
+	}
+}
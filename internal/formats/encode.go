@@ -0,0 +1,151 @@
+package formats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	JSONPretty = "json-pretty"
+	Export     = "export"
+)
+
+// Encode renders config data in the given output format. Dotenv and export
+// lines use environment variable names derived from the keys.
+func Encode(format string, data map[string]string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "", JSON:
+		return json.Marshal(data)
+	case JSONPretty:
+		return json.MarshalIndent(data, "", "  ")
+	case YAML, "yml":
+		return yaml.Marshal(data)
+	case TOML:
+		var b bytes.Buffer
+		if err := toml.NewEncoder(&b).Encode(data); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	case Properties:
+		return encodeProperties(data), nil
+	case Dotenv, "env":
+		return encodeEnv(data, func(name string, value string) string {
+			return name + "=" + quoteDotenv(value)
+		})
+	case Export:
+		return encodeEnv(data, func(name string, value string) string {
+			return "export " + name + "=" + quoteShell(value)
+		})
+	default:
+		return nil, fmt.Errorf("unknown output format '%s'", format)
+	}
+}
+
+// EnvName returns the environment variable name of a key: upper case with
+// every character that is not a letter or a digit replaced by an underscore.
+func EnvName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			name[i] = '_'
+		}
+	}
+
+	if len(name) != 0 && name[0] >= '0' && name[0] <= '9' {
+		return "_" + string(name)
+	}
+	return string(name)
+}
+
+func encodeEnv(data map[string]string, line func(name string, value string) string) ([]byte, error) {
+	keys := sortedKeys(data)
+
+	var b bytes.Buffer
+	names := map[string]string{}
+	for _, k := range keys {
+		name := EnvName(k)
+		if prev, found := names[name]; found {
+			return nil, fmt.Errorf("keys '%s' and '%s' have the same variable name '%s'", prev, k, name)
+		}
+		names[name] = k
+
+		b.WriteString(line(name, data[k]))
+		b.WriteByte('\n')
+	}
+
+	return b.Bytes(), nil
+}
+
+func quoteDotenv(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(value) + `"`
+}
+
+func quoteShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func encodeProperties(data map[string]string) []byte {
+	var b bytes.Buffer
+	for _, k := range sortedKeys(data) {
+		b.WriteString(escapeProperty(k, true))
+		b.WriteString("=")
+		b.WriteString(escapeProperty(data[k], false))
+		b.WriteByte('\n')
+	}
+
+	return b.Bytes()
+}
+
+// escapeProperty escapes a key or value of a properties file. Characters
+// outside of printable ASCII are written as unicode escapes.
+func escapeProperty(s string, key bool) string {
+	var b strings.Builder
+
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r == ' ' && (key || i == 0):
+			b.WriteString(`\ `)
+		case (r == '=' || r == ':') && key, (r == '#' || r == '!') && key && i == 0:
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+				fmt.Fprintf(&b, `\u%04x\u%04x`, r1, r2)
+			} else {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func sortedKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,152 @@
+package formats
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	data := map[string]string{
+		"db.host":  "localhost",
+		"db.port":  "5432",
+		"greeting": "it's \"café\"\n$HOME",
+	}
+
+	testTable := []struct {
+		name      string
+		format    string
+		expects   string
+		wantError bool
+	}{
+		{
+			name:    "OK JSON",
+			format:  JSON,
+			expects: `{"db.host":"localhost","db.port":"5432","greeting":"it's \"café\"\n$HOME"}`,
+		},
+		{
+			name:   "OK JSON Pretty",
+			format: JSONPretty,
+			expects: `{
+  "db.host": "localhost",
+  "db.port": "5432",
+  "greeting": "it's \"café\"\n$HOME"
+}`,
+		},
+		{
+			name:   "OK YAML",
+			format: YAML,
+			expects: `db.host: localhost
+db.port: "5432"
+greeting: |-
+    it's "café"
+    $HOME
+`,
+		},
+		{
+			name:   "OK TOML",
+			format: TOML,
+			expects: `"db.host" = "localhost"
+"db.port" = "5432"
+greeting = "it's \"café\"\n$HOME"
+`,
+		},
+		{
+			name:   "OK Properties",
+			format: Properties,
+			expects: `db.host=localhost
+db.port=5432
+greeting=it's "caf\u00e9"\n$HOME
+`,
+		},
+		{
+			name:   "OK Dotenv",
+			format: Dotenv,
+			expects: `DB_HOST="localhost"
+DB_PORT="5432"
+GREETING="it's \"café\"\n\$HOME"
+`,
+		},
+		{
+			name:   "OK Export",
+			format: Export,
+			expects: `export DB_HOST='localhost'
+export DB_PORT='5432'
+export GREETING='it'\''s "café"
+$HOME'
+`,
+		},
+		{
+			name:      "Unknown Format",
+			format:    "xml",
+			wantError: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := Encode(testCase.format, data)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, string(got))
+			}
+		})
+	}
+}
+
+func TestEncodeEnvNameCollision(t *testing.T) {
+	_, err := Encode(Dotenv, map[string]string{"db.host": "a", "db_host": "b"})
+	assert.EqualError(t, err, "keys 'db.host' and 'db_host' have the same variable name 'DB_HOST'")
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	data := map[string]string{
+		"key with spaces": " leading space",
+		"a=b:c":           "#not a comment",
+		"unicode":         "café 😀",
+		"multiline":       "one\ntwo\\",
+	}
+
+	for _, format := range []string{Properties, YAML} {
+		t.Run(format, func(t *testing.T) {
+			encoded, err := Encode(format, data)
+			assert.NoError(t, err)
+
+			if format == YAML {
+				encoded = append([]byte("data:\n"), indent(encoded)...)
+			}
+
+			document, err := ToJSON(format, encoded)
+			assert.NoError(t, err)
+
+			config := struct {
+				Data []map[string]string `json:"data"`
+			}{}
+			assert.NoError(t, json.Unmarshal(document, &config))
+
+			got := map[string]string{}
+			for _, pairs := range config.Data {
+				for k, v := range pairs {
+					got[k] = v
+				}
+			}
+			assert.Equal(t, data, got)
+		})
+	}
+}
+
+func indent(b []byte) []byte {
+	var indented []byte
+	lineStart := true
+	for _, c := range b {
+		if lineStart {
+			indented = append(indented, ' ', ' ')
+		}
+		indented = append(indented, c)
+		lineStart = c == '\n'
+	}
+
+	return indented
+}
@@ -0,0 +1,165 @@
+// Package formats converts config documents written in YAML, TOML, Java
+// properties or dotenv files into the JSON document accepted by
+// models.ServiceConfig and renders config data in those formats.
+package formats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	JSON       = "json"
+	YAML       = "yaml"
+	TOML       = "toml"
+	Properties = "properties"
+	Dotenv     = "dotenv"
+)
+
+const dataField = "data"
+
+// ParseError is a syntax error in a config document. Column is 0 when the
+// parser of the format does not report it.
+type ParseError struct {
+	Format string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("%s: line %d: %s", e.Format, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s: line %d, column %d: %s", e.Format, e.Line, e.Column, e.Msg)
+}
+
+// ToJSON converts the document into the JSON config document. The data of
+// structured formats may be nested, nested keys are joined with dots and lists
+// are stored as JSON arrays. Flat formats only carry data.
+func ToJSON(format string, document []byte) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "", JSON:
+		return document, nil
+	case YAML, "yml":
+		return parseYAML(document)
+	case TOML:
+		return parseTOML(document)
+	case Properties:
+		return parseProperties(document)
+	case Dotenv, "env":
+		return parseDotenv(document)
+	default:
+		return nil, fmt.Errorf("unknown config format '%s'", format)
+	}
+}
+
+// FlattenData returns the config data of decoded JSON data, nested objects are
+// joined with dots and lists are stored as JSON arrays.
+func FlattenData(data map[string]interface{}) (map[string]string, error) {
+	d := newFlatData(JSON)
+	if err := flattenMap(d, "", data); err != nil {
+		return nil, err
+	}
+	return d.values, nil
+}
+
+// flatData accumulates the flattened data of a document and remembers the
+// position each key was set at to report duplicates.
+type flatData struct {
+	format string
+	values map[string]string
+	lines  map[string][2]int
+}
+
+func newFlatData(format string) *flatData {
+	return &flatData{
+		format: format,
+		values: map[string]string{},
+		lines:  map[string][2]int{},
+	}
+}
+
+// set adds the key to the data, line is 0 for formats whose decoder does not
+// report the position of keys.
+func (d *flatData) set(key string, value string, line int, column int) error {
+	if key == "" && line == 0 {
+		return fmt.Errorf("%s: key is empty", d.format)
+	} else if key == "" {
+		return &ParseError{Format: d.format, Line: line, Column: column, Msg: "key is empty"}
+	}
+
+	if prev, found := d.lines[key]; found && line == 0 {
+		return fmt.Errorf("%s: key '%s' is defined more than once", d.format, key)
+	} else if found {
+		return &ParseError{
+			Format: d.format,
+			Line:   line,
+			Column: column,
+			Msg:    fmt.Sprintf("key '%s' already defined at line %d", key, prev[0]),
+		}
+	}
+
+	d.values[key] = value
+	d.lines[key] = [2]int{line, column}
+
+	return nil
+}
+
+// document returns the JSON config document made of the data and the other
+// top level fields.
+func (d *flatData) document(fields map[string]interface{}) ([]byte, error) {
+	keys := sortedKeys(d.values)
+
+	data := make([]map[string]string, 0, len(keys))
+	for _, k := range keys {
+		data = append(data, map[string]string{k: d.values[k]})
+	}
+
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	fields[dataField] = data
+
+	return json.Marshal(fields)
+}
+
+func joinKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// scalarString returns the config value of a decoded scalar.
+func scalarString(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		// whole numbers are written without exponent so that they stay ints
+		if v == math.Trunc(v) {
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v", v)
+	}
+}
+
+var errInvalidUnicodeEscape = errors.New("invalid unicode escape sequence")
@@ -0,0 +1,179 @@
+package formats
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestToJSON(t *testing.T) {
+	testTable := []struct {
+		name      string
+		format    string
+		document  string
+		expects   string
+		wantError string
+	}{
+		{
+			name:     "OK JSON",
+			format:   "",
+			document: `{"service":"billing","data":[{"port":"8080"}]}`,
+			expects:  `{"service":"billing","data":[{"port":"8080"}]}`,
+		},
+		{
+			name:   "OK YAML",
+			format: YAML,
+			document: `service: billing
+parent: base
+secrets: [db.password]
+data:
+  db:
+    host: localhost
+    port: 5432
+    password: s3cr3t
+  ratio: 0.10
+  hosts: [a, b]
+  debug: false
+  empty: ~
+`,
+			expects: `{"data":[{"db.host":"localhost"},{"db.password":"s3cr3t"},{"db.port":"5432"},{"debug":"false"},` +
+				`{"empty":""},{"hosts":"[\"a\",\"b\"]"},{"ratio":"0.10"}],"parent":"base","secrets":["db.password"],"service":"billing"}`,
+		},
+		{
+			name:     "OK YAML Data List",
+			format:   "yml",
+			document: "service: billing\ndata:\n  - port: 8080\n  - host: localhost\n",
+			expects:  `{"data":[{"host":"localhost"},{"port":"8080"}],"service":"billing"}`,
+		},
+		{
+			name:      "YAML Syntax Error",
+			format:    YAML,
+			document:  "service: billing\ndata:\n  port: 8080\n host: localhost\n",
+			wantError: "yaml: line 3: did not find expected key",
+		},
+		{
+			name:      "YAML Duplicate Key",
+			format:    YAML,
+			document:  "data:\n  db.host: a\n  db:\n    host: b\n",
+			wantError: "yaml: line 4, column 5: key 'db.host' already defined at line 2",
+		},
+		{
+			name:   "OK TOML",
+			format: TOML,
+			document: `service = "billing"
+secrets = ["db.password"]
+
+[data]
+ratio = 0.5
+hosts = ["a", "b"]
+
+[data.db]
+host = "localhost"
+port = 5432
+`,
+			expects: `{"data":[{"db.host":"localhost"},{"db.port":"5432"},{"hosts":"[\"a\",\"b\"]"},{"ratio":"0.5"}],` +
+				`"secrets":["db.password"],"service":"billing"}`,
+		},
+		{
+			name:      "TOML Syntax Error",
+			format:    TOML,
+			document:  "service = \"billing\"\n[data]\nport = 80 80\n",
+			wantError: "toml: line 3, column 10: expected a top-level item to end with a newline, comment, or EOF, but got '8' instead",
+		},
+		{
+			name:   "OK Properties",
+			format: Properties,
+			document: `# database
+db.host = localhost
+db.port:5432
+db.url jdbc:postgresql://localhost\
+       /billing
+greeting = café\ttime
+! comment
+key\ with\ spaces = value
+`,
+			expects: `{"data":[{"db.host":"localhost"},{"db.port":"5432"},{"db.url":"jdbc:postgresql://localhost/billing"},` +
+				`{"greeting":"café\ttime"},{"key with spaces":"value"}]}`,
+		},
+		{
+			name:      "Properties Invalid Escape",
+			format:    Properties,
+			document:  "db.host = localhost\nname = a\\u12x4\n",
+			wantError: "properties: line 2, column 9: invalid unicode escape sequence",
+		},
+		{
+			name:   "OK Dotenv",
+			format: Dotenv,
+			document: `# database
+export DB_HOST=localhost
+DB_PORT = 5432 # inline comment
+GREETING="hello\n\"world\""
+RAW='$HOME #not a comment'
+MULTI="line one
+line two"
+EMPTY=
+`,
+			expects: `{"data":[{"DB_HOST":"localhost"},{"DB_PORT":"5432"},{"EMPTY":""},{"GREETING":"hello\n\"world\""},` +
+				`{"MULTI":"line one\nline two"},{"RAW":"$HOME #not a comment"}]}`,
+		},
+		{
+			name:      "Dotenv Missing Equals",
+			format:    Dotenv,
+			document:  "DB_HOST=localhost\n  DB_PORT 5432\n",
+			wantError: "dotenv: line 2, column 15: expected '='",
+		},
+		{
+			name:      "Dotenv Unterminated Quote",
+			format:    Dotenv,
+			document:  "DB_HOST=localhost\nNAME=  \"value\n",
+			wantError: "dotenv: line 2, column 8: unterminated double quoted value",
+		},
+		{
+			name:      "Dotenv Trailing Characters",
+			format:    Dotenv,
+			document:  "NAME='value' extra\n",
+			wantError: "dotenv: line 1, column 14: unexpected characters after quoted value",
+		},
+		{
+			name:      "Unknown Format",
+			format:    "xml",
+			document:  "<config/>",
+			wantError: "unknown config format 'xml'",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := ToJSON(testCase.format, []byte(testCase.document))
+			if testCase.wantError != "" {
+				assert.EqualError(t, err, testCase.wantError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, string(got))
+			}
+		})
+	}
+}
+
+func TestFlattenData(t *testing.T) {
+	got, err := FlattenData(map[string]interface{}{
+		"db": map[string]interface{}{
+			"host": "localhost",
+			"port": float64(5432),
+			"tls":  true,
+		},
+		"max_bytes": float64(10485760),
+		"ratio":     0.25,
+		"hosts":     []interface{}{"a", "b"},
+		"empty":     nil,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"db.host":   "localhost",
+		"db.port":   "5432",
+		"db.tls":    "true",
+		"max_bytes": "10485760",
+		"ratio":     "0.25",
+		"hosts":     `["a","b"]`,
+		"empty":     "",
+	}, got)
+}
@@ -0,0 +1,156 @@
+package formats
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// parseProperties parses a Java properties file. Logical lines can be
+// continued with a trailing backslash and keys end at the first unescaped
+// '=', ':' or whitespace.
+func parseProperties(document []byte) ([]byte, error) {
+	d := newFlatData(Properties)
+	lines := strings.Split(strings.ReplaceAll(string(document), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := lines[i]
+
+		trimmed := strings.TrimLeft(line, " \t\f")
+		if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '!' {
+			continue
+		}
+		offset := len(line) - len(trimmed)
+
+		// columns maps every byte of the logical line to its column in the
+		// physical line it comes from.
+		logical := trimmed
+		columns := make([]int, 0, len(logical))
+		for n := range trimmed {
+			columns = append(columns, offset+n+1)
+		}
+		for continuesLine(logical) && i+1 < len(lines) {
+			logical = logical[:len(logical)-1]
+			columns = columns[:len(columns)-1]
+
+			i++
+			next := strings.TrimLeft(lines[i], " \t\f")
+			nextOffset := len(lines[i]) - len(next)
+			for n := range next {
+				columns = append(columns, nextOffset+n+1)
+			}
+			logical += next
+		}
+		column := func(n int) int {
+			if n >= len(columns) {
+				return columns[len(columns)-1] + 1
+			}
+			return columns[n]
+		}
+
+		keyEnd := len(logical)
+		for n := 0; n < len(logical); n++ {
+			if logical[n] == '\\' {
+				n++
+				continue
+			}
+			if strings.IndexByte("=: \t\f", logical[n]) != -1 {
+				keyEnd = n
+				break
+			}
+		}
+
+		valueStart := keyEnd
+		for valueStart < len(logical) && strings.IndexByte(" \t\f", logical[valueStart]) != -1 {
+			valueStart++
+		}
+		if valueStart < len(logical) && (logical[valueStart] == '=' || logical[valueStart] == ':') {
+			valueStart++
+			for valueStart < len(logical) && strings.IndexByte(" \t\f", logical[valueStart]) != -1 {
+				valueStart++
+			}
+		}
+
+		key, n, err := unescapeProperty(logical[:keyEnd])
+		if err != nil {
+			return nil, &ParseError{Format: Properties, Line: lineNumber, Column: column(n), Msg: err.Error()}
+		}
+		value, n, err := unescapeProperty(logical[valueStart:])
+		if err != nil {
+			return nil, &ParseError{Format: Properties, Line: lineNumber, Column: column(valueStart + n), Msg: err.Error()}
+		}
+
+		if err = d.set(key, value, lineNumber, column(0)); err != nil {
+			return nil, err
+		}
+	}
+
+	return d.document(nil)
+}
+
+// continuesLine reports whether the line ends with an odd number of
+// backslashes.
+func continuesLine(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// unescapeProperty resolves the escape sequences of a key or value. On error
+// it returns the offset of the invalid sequence.
+func unescapeProperty(s string) (string, int, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 == len(s) {
+			break
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", i - 1, errInvalidUnicodeEscape
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", i - 1, errInvalidUnicodeEscape
+			}
+			i += 4
+
+			// characters outside of the BMP are written as surrogate pairs
+			if utf16.IsSurrogate(rune(r)) && strings.HasPrefix(s[i+1:], `\u`) && i+7 <= len(s) {
+				if low, err := strconv.ParseUint(s[i+3:i+7], 16, 32); err == nil {
+					if pair := utf16.DecodeRune(rune(r), rune(low)); pair != utf8.RuneError {
+						b.WriteRune(pair)
+						i += 6
+						continue
+					}
+				}
+			}
+			b.WriteRune(rune(r))
+		default:
+			_, size := utf8.DecodeRuneInString(s[i:])
+			b.WriteString(s[i : i+size])
+			i += size - 1
+		}
+	}
+
+	return b.String(), 0, nil
+}
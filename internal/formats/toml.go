@@ -0,0 +1,108 @@
+package formats
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"regexp"
+	"sort"
+)
+
+var tomlErrorPrefix = regexp.MustCompile(`^toml: line \d+( \(last key "[^"]*"\))?: `)
+
+func parseTOML(document []byte) ([]byte, error) {
+	m := map[string]interface{}{}
+	if _, err := toml.Decode(string(document), &m); err != nil {
+		return nil, tomlError(document, err)
+	}
+
+	d := newFlatData(TOML)
+	fields := map[string]interface{}{}
+	for k, v := range m {
+		if k != dataField {
+			fields[k] = v
+			continue
+		}
+
+		switch data := v.(type) {
+		case map[string]interface{}:
+			if err := flattenMap(d, "", data); err != nil {
+				return nil, err
+			}
+		case []map[string]interface{}:
+			for _, item := range data {
+				if err := flattenMap(d, "", item); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, fmt.Errorf("%s: data must be a table", TOML)
+		}
+	}
+
+	return d.document(fields)
+}
+
+// flattenMap sets the scalar values of the decoded table in the data, nested
+// tables are joined with dots and lists are stored as JSON arrays.
+func flattenMap(d *flatData, prefix string, table map[string]interface{}) error {
+	keys := make([]string, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key := joinKey(prefix, k)
+
+		var value string
+		switch v := table[k].(type) {
+		case map[string]interface{}:
+			if err := flattenMap(d, key, v); err != nil {
+				return err
+			}
+			continue
+		case []interface{}, []map[string]interface{}:
+			array, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			value = string(array)
+		default:
+			var err error
+			if value, err = scalarString(v); err != nil {
+				return fmt.Errorf("%s: key '%s': %s", d.format, key, err)
+			}
+		}
+
+		if err := d.set(key, value, 0, 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tomlError converts the errors of the TOML parser into parse errors with the
+// column computed from the byte offset of the error.
+func tomlError(document []byte, err error) error {
+	var parseError toml.ParseError
+	if !errors.As(err, &parseError) {
+		return err
+	}
+
+	start := parseError.Position.Start
+	if start > len(document) {
+		start = len(document)
+	}
+	column := start - bytes.LastIndexByte(document[:start], '\n')
+
+	msg := parseError.Message
+	if msg == "" {
+		msg = tomlErrorPrefix.ReplaceAllString(parseError.Error(), "")
+	}
+
+	return &ParseError{Format: TOML, Line: parseError.Position.Line, Column: column, Msg: msg}
+}
@@ -0,0 +1,139 @@
+package formats
+
+import (
+	"encoding/json"
+	"errors"
+	"gopkg.in/yaml.v3"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var yamlErrorPattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+func parseYAML(document []byte) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(document, &root); err != nil {
+		return nil, yamlError(err)
+	}
+
+	d := newFlatData(YAML)
+	fields := map[string]interface{}{}
+	if len(root.Content) == 0 {
+		return d.document(fields)
+	}
+
+	doc := resolveYAMLAlias(root.Content[0])
+	if doc.Kind != yaml.MappingNode {
+		return nil, &ParseError{Format: YAML, Line: doc.Line, Column: doc.Column, Msg: "document must be a mapping"}
+	}
+
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		k, v := doc.Content[i], doc.Content[i+1]
+		if k.Value == dataField {
+			if err := flattenYAML(d, "", v); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var field interface{}
+		if err := v.Decode(&field); err != nil {
+			return nil, yamlError(err)
+		}
+		fields[k.Value] = field
+	}
+
+	return d.document(fields)
+}
+
+// flattenYAML adds the values of the mapping to the data. The data can also
+// be a sequence of mappings like in JSON documents.
+func flattenYAML(d *flatData, prefix string, n *yaml.Node) error {
+	n = resolveYAMLAlias(n)
+
+	switch {
+	case n.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], resolveYAMLAlias(n.Content[i+1])
+			key := joinKey(prefix, k.Value)
+
+			if v.Kind == yaml.MappingNode {
+				if err := flattenYAML(d, key, v); err != nil {
+					return err
+				}
+				continue
+			}
+
+			value, err := yamlValue(v)
+			if err != nil {
+				return err
+			}
+			if err = d.set(key, value, k.Line, k.Column); err != nil {
+				return err
+			}
+		}
+	case n.Kind == yaml.SequenceNode && prefix == "":
+		for _, item := range n.Content {
+			if resolveYAMLAlias(item).Kind != yaml.MappingNode {
+				return &ParseError{Format: YAML, Line: item.Line, Column: item.Column, Msg: "data items must be mappings"}
+			}
+			if err := flattenYAML(d, "", item); err != nil {
+				return err
+			}
+		}
+	default:
+		return &ParseError{Format: YAML, Line: n.Line, Column: n.Column, Msg: "data must be a mapping"}
+	}
+
+	return nil
+}
+
+// yamlValue returns the config value of a scalar or sequence node. Scalars
+// keep their text as written, sequences become JSON arrays.
+func yamlValue(n *yaml.Node) (string, error) {
+	if n.Kind == yaml.ScalarNode {
+		if n.ShortTag() == "!!null" {
+			return "", nil
+		}
+		return n.Value, nil
+	}
+
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return "", yamlError(err)
+	}
+
+	value, err := json.Marshal(v)
+	if err != nil {
+		return "", &ParseError{Format: YAML, Line: n.Line, Column: n.Column, Msg: err.Error()}
+	}
+
+	return string(value), nil
+}
+
+func resolveYAMLAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
+
+// yamlError converts the errors of the YAML parser, which only report the
+// line, into parse errors.
+func yamlError(err error) error {
+	msg := err.Error()
+
+	var typeError *yaml.TypeError
+	if errors.As(err, &typeError) && len(typeError.Errors) != 0 {
+		msg = typeError.Errors[0]
+	}
+
+	match := yamlErrorPattern.FindStringSubmatch(strings.TrimSpace(msg))
+	if match == nil {
+		return err
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	return &ParseError{Format: YAML, Line: line, Msg: match[2]}
+}
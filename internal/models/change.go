@@ -0,0 +1,14 @@
+package models
+
+const (
+	ChangeVersion = "version"
+	ChangeDeleted = "deleted"
+)
+
+// ConfigChange is a change of a service config notified by the database.
+// A deletion with version 0 is the deletion of the whole service config.
+type ConfigChange struct {
+	Service string `json:"service"`
+	Version uint32 `json:"version"`
+	Kind    string `json:"kind"`
+}
@@ -0,0 +1,24 @@
+package models
+
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// Changeset groups operations on several services that are applied together
+// or not at all. Every version it creates records its ID.
+type Changeset struct {
+	ID         int
+	Message    string
+	Operations []*ChangesetOperation
+}
+
+// ChangesetOperation creates, updates or deletes the config of a service. An
+// update, or a delete with a BaseVersion, is applied only if BaseVersion is
+// still the latest version.
+type ChangesetOperation struct {
+	Kind        string
+	Config      *ServiceConfig
+	BaseVersion uint32
+}
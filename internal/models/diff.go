@@ -0,0 +1,218 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	KeyAdded   = "added"
+	KeyRemoved = "removed"
+	KeyChanged = "changed"
+)
+
+type KeyChange struct {
+	Key      string
+	Kind     string
+	OldValue string
+	NewValue string
+}
+
+// DiffData returns the changes that turn the old data into the new one sorted
+// by key.
+func DiffData(oldData, newData map[string]string) []KeyChange {
+	var changes []KeyChange
+
+	for k, v := range oldData {
+		if nv, found := newData[k]; !found {
+			changes = append(changes, KeyChange{Key: k, Kind: KeyRemoved, OldValue: v})
+		} else if nv != v {
+			changes = append(changes, KeyChange{Key: k, Kind: KeyChanged, OldValue: v, NewValue: nv})
+		}
+	}
+	for k, v := range newData {
+		if _, found := oldData[k]; !found {
+			changes = append(changes, KeyChange{Key: k, Kind: KeyAdded, NewValue: v})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// ChangedKeys returns the sorted keys that were added, removed or changed in
+// the new data compared to the old one.
+func ChangedKeys(oldData, newData map[string]string) []string {
+	var keys []string
+
+	for _, change := range DiffData(oldData, newData) {
+		keys = append(keys, change.Key)
+	}
+
+	return keys
+}
+
+// PatchOperation is an operation of a JSON Patch document (RFC 6902) on the
+// flat config data.
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON writes the value of add, replace and test operations even when
+// it is empty, as RFC 6902 requires it for them.
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	type operation PatchOperation
+
+	switch op.Op {
+	case "add", "replace", "test":
+		return json.Marshal(struct {
+			operation
+			Value interface{} `json:"value"`
+		}{operation(op), op.Value})
+	default:
+		return json.Marshal(operation(op))
+	}
+}
+
+// JSONPatch returns the JSON Patch operations that apply the changes.
+func JSONPatch(changes []KeyChange) []PatchOperation {
+	operations := make([]PatchOperation, 0, len(changes))
+
+	for _, change := range changes {
+		path := patchPath(change.Key)
+
+		switch change.Kind {
+		case KeyAdded:
+			operations = append(operations, PatchOperation{Op: "add", Path: path, Value: change.NewValue})
+		case KeyRemoved:
+			operations = append(operations, PatchOperation{Op: "remove", Path: path})
+		case KeyChanged:
+			operations = append(operations, PatchOperation{Op: "replace", Path: path, Value: change.NewValue})
+		}
+	}
+
+	return operations
+}
+
+// UnifiedDiff returns the unified diff of the changes to the old data, written
+// as sorted "key=value" lines with three lines of context around changes.
+func UnifiedDiff(oldLabel, newLabel string, oldData map[string]string, changes []KeyChange) string {
+	const context = 3
+
+	type line struct {
+		op   byte
+		text string
+	}
+
+	changed := make(map[string]KeyChange, len(changes))
+	keys := make([]string, 0, len(oldData)+len(changes))
+	for k := range oldData {
+		keys = append(keys, k)
+	}
+	for _, change := range changes {
+		changed[change.Key] = change
+		if change.Kind == KeyAdded {
+			keys = append(keys, change.Key)
+		}
+	}
+	sort.Strings(keys)
+
+	var lines []line
+	for _, k := range keys {
+		change, found := changed[k]
+		if !found {
+			lines = append(lines, line{' ', diffLine(k, oldData[k])})
+			continue
+		}
+		if change.Kind != KeyAdded {
+			lines = append(lines, line{'-', diffLine(k, change.OldValue)})
+		}
+		if change.Kind != KeyRemoved {
+			lines = append(lines, line{'+', diffLine(k, change.NewValue)})
+		}
+	}
+
+	var b strings.Builder
+	oldLine, newLine := 1, 1
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			i++
+			oldLine++
+			newLine++
+			continue
+		}
+
+		// a hunk starts with the context before the first change and ends
+		// once more than twice the context separates two changes
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for n := i; n < len(lines) && n <= end+2*context; n++ {
+			if lines[n].op != ' ' {
+				end = n
+			}
+		}
+		end += context
+		if end >= len(lines) {
+			end = len(lines) - 1
+		}
+
+		hunkOld, hunkNew := oldLine-(i-start), newLine-(i-start)
+		var oldCount, newCount int
+		for _, l := range lines[start : end+1] {
+			if l.op != '+' {
+				oldCount++
+			}
+			if l.op != '-' {
+				newCount++
+			}
+		}
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldLabel, newLabel)
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(hunkOld, oldCount), hunkRange(hunkNew, newCount))
+		for _, l := range lines[start : end+1] {
+			b.WriteByte(l.op)
+			b.WriteString(l.text)
+			b.WriteByte('\n')
+		}
+
+		for _, l := range lines[i : end+1] {
+			if l.op != '+' {
+				oldLine++
+			}
+			if l.op != '-' {
+				newLine++
+			}
+		}
+		i = end + 1
+	}
+
+	return b.String()
+}
+
+func diffLine(key string, value string) string {
+	return key + "=" + strings.NewReplacer("\\", "\\\\", "\n", "\\n").Replace(value)
+}
+
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiffData(t *testing.T) {
+	oldData := map[string]string{"k1": "v1", "k2": "v2", "k3": "v3"}
+	newData := map[string]string{"k1": "v1", "k2": "changed", "k4": "v4"}
+
+	assert.Equal(t, []KeyChange{
+		{Key: "k2", Kind: KeyChanged, OldValue: "v2", NewValue: "changed"},
+		{Key: "k3", Kind: KeyRemoved, OldValue: "v3"},
+		{Key: "k4", Kind: KeyAdded, NewValue: "v4"},
+	}, DiffData(oldData, newData))
+	assert.Empty(t, DiffData(oldData, oldData))
+}
+
+func TestChangedKeys(t *testing.T) {
+	a := map[string]string{"k1": "v1", "k2": "v2", "k3": "v3"}
+	b := map[string]string{"k1": "v1", "k2": "changed", "k4": "v4"}
+
+	assert.Equal(t, []string{"k2", "k3", "k4"}, ChangedKeys(a, b))
+	assert.Empty(t, ChangedKeys(a, a))
+}
+
+func TestJSONPatch(t *testing.T) {
+	changes := []KeyChange{
+		{Key: "a/b", Kind: KeyChanged, OldValue: "v2", NewValue: ""},
+		{Key: "k3", Kind: KeyRemoved, OldValue: "v3"},
+		{Key: "m~n", Kind: KeyAdded, NewValue: "v4"},
+	}
+
+	assert.Equal(t, []PatchOperation{
+		{Op: "replace", Path: "/a~1b", Value: ""},
+		{Op: "remove", Path: "/k3"},
+		{Op: "add", Path: "/m~0n", Value: "v4"},
+	}, JSONPatch(changes))
+}
+
+func TestPatchOperationMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(JSONPatch([]KeyChange{
+		{Key: "k1", Kind: KeyChanged, OldValue: "v1", NewValue: ""},
+		{Key: "k2", Kind: KeyRemoved, OldValue: "v2"},
+		{Key: "k3", Kind: KeyAdded, NewValue: ""},
+	}))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"replace","path":"/k1","value":""},{"op":"remove","path":"/k2"},`+
+		`{"op":"add","path":"/k3","value":""}]`, string(data))
+
+	patched, err := ApplyJSONPatch(map[string]string{"k1": "v1", "k2": "v2"}, data)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"k1": "", "k3": ""}, patched)
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	oldData := map[string]string{}
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		oldData[k] = k
+	}
+
+	testTable := []struct {
+		name    string
+		changes []KeyChange
+		expects string
+	}{
+		{
+			name:    "NoChanges",
+			expects: "",
+		},
+		{
+			name: "OneHunk",
+			changes: []KeyChange{
+				{Key: "a", Kind: KeyChanged, OldValue: "a", NewValue: "x\ny"},
+				{Key: "c", Kind: KeyRemoved, OldValue: "c"},
+			},
+			expects: "--- old\n+++ new\n@@ -1,6 +1,5 @@\n-a=a\n+a=x\\ny\n b=b\n-c=c\n d=d\n e=e\n f=f\n",
+		},
+		{
+			name: "TwoHunks",
+			changes: []KeyChange{
+				{Key: "b", Kind: KeyRemoved, OldValue: "b"},
+				{Key: "ka", Kind: KeyAdded, NewValue: "new"},
+			},
+			expects: "--- old\n+++ new\n@@ -1,5 +1,4 @@\n a=a\n-b=b\n c=c\n d=d\n e=e\n" +
+				"@@ -9,4 +8,5 @@\n i=i\n j=j\n k=k\n+ka=new\n l=l\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expects, UnifiedDiff("old", "new", oldData, testCase.changes))
+		})
+	}
+}
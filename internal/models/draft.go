@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Draft is a config version saved for review. It is not served by Read until
+// it is published on top of the version it is based on.
+type Draft struct {
+	ID          int
+	BaseVersion uint32
+	CreatedAt   time.Time
+	Config      *ServiceConfig
+}
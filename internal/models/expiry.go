@@ -0,0 +1,116 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Expiry is the time after which the value of a key is no longer served.
+// The key then takes its prior value or, if it had none, it is removed and
+// the value of the parents shows through.
+type Expiry struct {
+	At    time.Time `json:"at"`
+	Prior *string   `json:"prior,omitempty"`
+}
+
+type ExpiringKey struct {
+	Service  string
+	Version  uint32
+	Key      string
+	At       time.Time
+	HasPrior bool
+}
+
+// SetExpiryPriors records the value every expiring key of the service config
+// had in prev. An override that was already expiring in prev keeps the prior
+// value recorded there.
+func SetExpiryPriors(sc *ServiceConfig, prev *ServiceConfig) {
+	for k, e := range sc.Spec.Expires {
+		e.Prior = nil
+		if prev != nil {
+			if prevExpiry, found := prev.Spec.Expires[k]; found {
+				e.Prior = prevExpiry.Prior
+			} else if v, found := prev.Data[k]; found {
+				e.Prior = &v
+			}
+		}
+		sc.Spec.Expires[k] = e
+	}
+}
+
+// WithoutExpired returns a copy of the service config in which the keys
+// expired at now have been reverted to their prior value.
+func (s *ServiceConfig) WithoutExpired(now time.Time) *ServiceConfig {
+	if len(s.Spec.Expires) == 0 {
+		return s
+	}
+
+	sc := *s
+	sc.Data = make(map[string]string, len(s.Data))
+	for k, v := range s.Data {
+		sc.Data[k] = v
+	}
+	sc.Spec.Expires = make(map[string]Expiry, len(s.Spec.Expires))
+	for k, e := range s.Spec.Expires {
+		sc.Spec.Expires[k] = e
+	}
+
+	sc.ExpireKeys(now)
+
+	return &sc
+}
+
+// ExpireKeys reverts the keys expired at now to their prior value and records
+// them as expired in the spec. It returns the expired keys.
+func (s *ServiceConfig) ExpireKeys(now time.Time) []string {
+	var expired []string
+
+	for k, e := range s.Spec.Expires {
+		if e.At.After(now) {
+			continue
+		}
+
+		if e.Prior != nil {
+			s.Data[k] = *e.Prior
+		} else {
+			delete(s.Data, k)
+		}
+		delete(s.Spec.Expires, k)
+		expired = append(expired, k)
+	}
+	sort.Strings(expired)
+
+	if len(s.Spec.Expires) == 0 {
+		s.Spec.Expires = nil
+	}
+	s.Spec.Expired = expired
+
+	return expired
+}
+
+// ExpiringKeys returns the keys of the service config expiring at or before
+// the given time, sorted by expiry time.
+func (s *ServiceConfig) ExpiringKeys(before time.Time) []ExpiringKey {
+	var keys []ExpiringKey
+
+	for k, e := range s.Spec.Expires {
+		if !e.At.After(before) {
+			keys = append(keys, ExpiringKey{
+				Service:  s.Service,
+				Version:  s.Version,
+				Key:      k,
+				At:       e.At,
+				HasPrior: e.Prior != nil,
+			})
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].At.Equal(keys[j].At) {
+			return keys[i].Key < keys[j].Key
+		}
+		return keys[i].At.Before(keys[j].At)
+	})
+
+	return keys
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSetExpiryPriors(t *testing.T) {
+	at := time.Date(2026, 10, 23, 18, 0, 0, 0, time.UTC)
+	prior := "100"
+
+	prev := &ServiceConfig{
+		Data: map[string]string{"rate.limit": "500", "timeout": "5s"},
+		Spec: Spec{Expires: map[string]Expiry{"rate.limit": {At: at, Prior: &prior}}},
+	}
+	sc := &ServiceConfig{
+		Data: map[string]string{"rate.limit": "800", "timeout": "30s", "boost": "on"},
+		Spec: Spec{Expires: map[string]Expiry{
+			"rate.limit": {At: at.Add(time.Hour)},
+			"timeout":    {At: at},
+			"boost":      {At: at},
+		}},
+	}
+
+	SetExpiryPriors(sc, prev)
+
+	assert.Equal(t, "100", *sc.Spec.Expires["rate.limit"].Prior)
+	assert.Equal(t, "5s", *sc.Spec.Expires["timeout"].Prior)
+	assert.Nil(t, sc.Spec.Expires["boost"].Prior)
+}
+
+func TestWithoutExpired(t *testing.T) {
+	at := time.Date(2026, 10, 23, 18, 0, 0, 0, time.UTC)
+	prior := "100"
+
+	sc := &ServiceConfig{
+		Service: "test1",
+		Data:    map[string]string{"rate.limit": "500", "boost": "on", "timeout": "5s"},
+		Spec: Spec{Expires: map[string]Expiry{
+			"rate.limit": {At: at, Prior: &prior},
+			"boost":      {At: at.Add(-time.Hour)},
+			"timeout":    {At: at.Add(time.Hour)},
+		}},
+	}
+
+	expired := sc.WithoutExpired(at)
+
+	assert.Equal(t, map[string]string{"rate.limit": "100", "timeout": "5s"}, expired.Data)
+	assert.Equal(t, []string{"boost", "rate.limit"}, expired.Spec.Expired)
+	assert.Len(t, sc.Data, 3)
+	assert.Len(t, sc.Spec.Expires, 3)
+
+	keys := sc.ExpiringKeys(at)
+	assert.Equal(t, []ExpiringKey{
+		{Service: "test1", Key: "boost", At: at.Add(-time.Hour)},
+		{Service: "test1", Key: "rate.limit", At: at, HasPrior: true},
+	}, keys)
+}
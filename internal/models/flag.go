@@ -0,0 +1,140 @@
+package models
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+)
+
+const (
+	FlagReasonKilled    = "KILLED"
+	FlagReasonRuleMatch = "RULE_MATCH"
+	FlagReasonRollout   = "ROLLOUT"
+	FlagReasonDefault   = "DEFAULT"
+)
+
+// Flag is a feature flag evaluated against the context of a client. A killed
+// flag always takes its default value, otherwise the first matching targeting
+// rule decides the value, then the percentage rollout for clients with an
+// identifier.
+type Flag struct {
+	Default string     `json:"default"`
+	Killed  bool       `json:"killed,omitempty"`
+	Rules   []FlagRule `json:"rules,omitempty"`
+	Rollout *Rollout   `json:"rollout,omitempty"`
+}
+
+type FlagRule struct {
+	When  string `json:"when"`
+	Value string `json:"value"`
+}
+
+// Rollout serves Value to Percentage percent of the identifiers, the others
+// get the default value.
+type Rollout struct {
+	Percentage uint32 `json:"percentage"`
+	Value      string `json:"value"`
+}
+
+type EvaluatedFlag struct {
+	Name   string
+	Value  string
+	Reason string
+}
+
+// LayerFlags returns the flags declared by the layers, a flag declared by a
+// more specific layer replaces the flag of its parents.
+func LayerFlags(layers []*ServiceConfig) map[string]Flag {
+	flags := map[string]Flag{}
+	for _, layer := range layers {
+		for name, f := range layer.Spec.Flags {
+			flags[name] = f
+		}
+	}
+
+	return flags
+}
+
+// EvaluateFlags evaluates the named flags or every flag if no name is given.
+// The result is sorted by flag name.
+func EvaluateFlags(flags map[string]Flag, names []string, identifier string, context map[string]string) ([]EvaluatedFlag, error) {
+	if len(names) == 0 {
+		for name := range flags {
+			names = append(names, name)
+		}
+	} else {
+		names = append([]string(nil), names...)
+	}
+	sort.Strings(names)
+
+	evaluated := make([]EvaluatedFlag, 0, len(names))
+	for _, name := range names {
+		f, found := flags[name]
+		if !found {
+			return nil, fmt.Errorf("flag '%s' not found", name)
+		}
+
+		value, reason, err := f.Evaluate(name, identifier, context)
+		if err != nil {
+			return nil, err
+		}
+		evaluated = append(evaluated, EvaluatedFlag{Name: name, Value: value, Reason: reason})
+	}
+
+	return evaluated, nil
+}
+
+func (f *Flag) Evaluate(name string, identifier string, context map[string]string) (string, string, error) {
+	if f.Killed {
+		return f.Default, FlagReasonKilled, nil
+	}
+
+	for _, r := range f.Rules {
+		selector, err := ParseSelector(r.When)
+		if err != nil {
+			return "", "", err
+		}
+		if selector.Matches(context) {
+			return r.Value, FlagReasonRuleMatch, nil
+		}
+	}
+
+	if f.Rollout != nil && identifier != "" && rolloutBucket(name, identifier) < f.Rollout.Percentage {
+		return f.Rollout.Value, FlagReasonRollout, nil
+	}
+
+	return f.Default, FlagReasonDefault, nil
+}
+
+// rolloutBucket places the identifier in one of 100 buckets. The bucket only
+// depends on the flag name and the identifier, so a client keeps its value
+// while the rollout percentage grows.
+func rolloutBucket(name string, identifier string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(name + "/" + identifier))
+
+	return h.Sum32() % 100
+}
+
+func validateFlags(flags map[string]Flag) error {
+	for name, f := range flags {
+		if name == "" || !labelPattern.MatchString(name) {
+			return fmt.Errorf("invalid flag name '%s'", name)
+		}
+
+		for _, r := range f.Rules {
+			selector, err := ParseSelector(r.When)
+			if err != nil {
+				return err
+			} else if len(selector.requirements) == 0 {
+				return fmt.Errorf("rule of flag '%s' has no condition", name)
+			}
+		}
+
+		if f.Rollout != nil && f.Rollout.Percentage > 100 {
+			return fmt.Errorf("rollout percentage of flag '%s' is greater than 100", name)
+		}
+	}
+
+	return nil
+}
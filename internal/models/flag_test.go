@@ -0,0 +1,130 @@
+package models
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFlagEvaluate(t *testing.T) {
+	testTable := []struct {
+		name          string
+		flag          Flag
+		identifier    string
+		context       map[string]string
+		expects       string
+		expectsReason string
+	}{
+		{
+			name:          "OK Default",
+			flag:          Flag{Default: "false"},
+			identifier:    "user-1",
+			expects:       "false",
+			expectsReason: FlagReasonDefault,
+		},
+		{
+			name: "OK Killed",
+			flag: Flag{
+				Default: "false",
+				Killed:  true,
+				Rules:   []FlagRule{{When: "team=payments", Value: "true"}},
+				Rollout: &Rollout{Percentage: 100, Value: "true"},
+			},
+			identifier:    "user-1",
+			context:       map[string]string{"team": "payments"},
+			expects:       "false",
+			expectsReason: FlagReasonKilled,
+		},
+		{
+			name: "OK Rule Match",
+			flag: Flag{
+				Default: "false",
+				Rules:   []FlagRule{{When: "team=payments", Value: "true"}},
+			},
+			context:       map[string]string{"team": "payments"},
+			expects:       "true",
+			expectsReason: FlagReasonRuleMatch,
+		},
+		{
+			name: "OK Full Rollout",
+			flag: Flag{
+				Default: "false",
+				Rules:   []FlagRule{{When: "team=payments", Value: "true"}},
+				Rollout: &Rollout{Percentage: 100, Value: "true"},
+			},
+			identifier:    "user-1",
+			context:       map[string]string{"team": "search"},
+			expects:       "true",
+			expectsReason: FlagReasonRollout,
+		},
+		{
+			name:          "OK Rollout Without Identifier",
+			flag:          Flag{Default: "false", Rollout: &Rollout{Percentage: 100, Value: "true"}},
+			expects:       "false",
+			expectsReason: FlagReasonDefault,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			value, reason, err := testCase.flag.Evaluate("checkout", testCase.identifier, testCase.context)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expects, value)
+			assert.Equal(t, testCase.expectsReason, reason)
+		})
+	}
+}
+
+func TestFlagRolloutIsStable(t *testing.T) {
+	rolledOut := func(percentage uint32) map[string]bool {
+		f := Flag{Default: "false", Rollout: &Rollout{Percentage: percentage, Value: "true"}}
+
+		ids := map[string]bool{}
+		for i := 0; i < 1000; i++ {
+			id := fmt.Sprintf("user-%d", i)
+			if value, _, _ := f.Evaluate("checkout", id, nil); value == "true" {
+				ids[id] = true
+			}
+		}
+		return ids
+	}
+
+	small, large := rolledOut(10), rolledOut(50)
+	assert.InDelta(t, 100, len(small), 40)
+	assert.InDelta(t, 500, len(large), 80)
+	for id := range small {
+		assert.True(t, large[id], id)
+	}
+}
+
+func TestEvaluateFlags(t *testing.T) {
+	base := &ServiceConfig{
+		Service: "base",
+		Spec: Spec{Flags: map[string]Flag{
+			"checkout": {Default: "false"},
+			"search":   {Default: "v1"},
+		}},
+	}
+	prod := &ServiceConfig{
+		Service: "prod",
+		Spec:    Spec{Flags: map[string]Flag{"checkout": {Default: "true"}}},
+	}
+
+	flags := LayerFlags([]*ServiceConfig{base, prod})
+
+	evaluated, err := EvaluateFlags(flags, nil, "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []EvaluatedFlag{
+		{Name: "checkout", Value: "true", Reason: FlagReasonDefault},
+		{Name: "search", Value: "v1", Reason: FlagReasonDefault},
+	}, evaluated)
+
+	names := []string{"search", "checkout"}
+	evaluated, err = EvaluateFlags(flags, names, "", nil)
+	assert.NoError(t, err)
+	assert.Len(t, evaluated, 2)
+	assert.Equal(t, []string{"search", "checkout"}, names)
+
+	_, err = EvaluateFlags(flags, []string{"missing"}, "", nil)
+	assert.Error(t, err)
+}
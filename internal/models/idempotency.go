@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// IdempotencyKey records the result of a mutating request sent with a key, so
+// that retries of the request get the original result instead of executing it
+// again.
+type IdempotencyKey struct {
+	Key         string
+	Method      string
+	RequestHash string
+	Response    []byte
+	CreatedAt   time.Time
+}
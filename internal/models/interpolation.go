@@ -0,0 +1,124 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// referencePattern matches "${key}" and "${service:key}" references, a
+// reference prefixed with one more "$" is an escaped literal.
+var referencePattern = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+// DataLoader returns the data of another service to resolve cross-service
+// references against.
+type DataLoader func(serviceName string) (map[string]string, error)
+
+type interpolator struct {
+	load      DataLoader
+	data      map[string]map[string]string
+	rendered  map[string]string
+	resolving []string
+}
+
+// Interpolate returns data of the service with every "${key}" reference
+// replaced by the value of the key and every "${service:key}" reference
+// replaced by the value of the key in the config of another service loaded
+// with load. Unresolved references and reference cycles are errors.
+func Interpolate(serviceName string, data map[string]string, load DataLoader) (map[string]string, error) {
+	i := &interpolator{
+		load:     load,
+		data:     map[string]map[string]string{serviceName: data},
+		rendered: map[string]string{},
+	}
+
+	result := make(map[string]string, len(data))
+	for k := range data {
+		v, err := i.resolve(serviceName, k)
+		if err != nil {
+			return nil, err
+		}
+		result[k] = v
+	}
+
+	return result, nil
+}
+
+func (i *interpolator) resolve(serviceName, key string) (string, error) {
+	id := serviceName + ":" + key
+	if v, found := i.rendered[id]; found {
+		return v, nil
+	}
+
+	for n, resolving := range i.resolving {
+		if resolving == id {
+			cycle := append(append([]string{}, i.resolving[n:]...), id)
+			return "", fmt.Errorf("reference cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	data, err := i.serviceData(serviceName)
+	if err != nil {
+		return "", err
+	}
+	raw := data[key]
+
+	i.resolving = append(i.resolving, id)
+	defer func() {
+		i.resolving = i.resolving[:len(i.resolving)-1]
+	}()
+
+	var resolveErr error
+	v := referencePattern.ReplaceAllStringFunc(raw, func(match string) string {
+		if resolveErr != nil {
+			return match
+		}
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+
+		refService, refKey := serviceName, match[2:len(match)-1]
+		if n := strings.Index(refKey, ":"); n != -1 {
+			refService, refKey = refKey[:n], refKey[n+1:]
+		}
+
+		refData, err := i.serviceData(refService)
+		if err != nil {
+			resolveErr = fmt.Errorf("unresolved reference '%s' in key '%s' of service '%s': %w", match, key, serviceName, err)
+			return match
+		}
+		if _, found := refData[refKey]; !found {
+			resolveErr = fmt.Errorf("unresolved reference '%s' in key '%s' of service '%s'", match, key, serviceName)
+			return match
+		}
+
+		refValue, err := i.resolve(refService, refKey)
+		if err != nil {
+			resolveErr = err
+			return match
+		}
+
+		return refValue
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+
+	i.rendered[id] = v
+
+	return v, nil
+}
+
+func (i *interpolator) serviceData(serviceName string) (map[string]string, error) {
+	if data, found := i.data[serviceName]; found {
+		return data, nil
+	}
+
+	data, err := i.load(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	i.data[serviceName] = data
+
+	return data, nil
+}
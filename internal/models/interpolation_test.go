@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	services := map[string]map[string]string{
+		"shared/network": {
+			"proxy.host": "proxy.local",
+			"proxy.url":  "http://${proxy.host}:3128",
+		},
+	}
+	load := func(serviceName string) (map[string]string, error) {
+		data, found := services[serviceName]
+		if !found {
+			return nil, fmt.Errorf("config for service '%s' not found", serviceName)
+		}
+		return data, nil
+	}
+
+	testTable := []struct {
+		name      string
+		data      map[string]string
+		expects   map[string]string
+		wantError bool
+	}{
+		{
+			name: "OK",
+			data: map[string]string{
+				"db.host": "localhost",
+				"db.url":  "postgres://${db.host}/app",
+				"proxy":   "${shared/network:proxy.url}",
+				"literal": "$${db.host}",
+			},
+			expects: map[string]string{
+				"db.host": "localhost",
+				"db.url":  "postgres://localhost/app",
+				"proxy":   "http://proxy.local:3128",
+				"literal": "${db.host}",
+			},
+		},
+		{
+			name:      "UnresolvedKey",
+			data:      map[string]string{"db.url": "postgres://${db.host}/app"},
+			wantError: true,
+		},
+		{
+			name:      "UnresolvedService",
+			data:      map[string]string{"proxy": "${dont-exist:proxy.url}"},
+			wantError: true,
+		},
+		{
+			name:      "Cycle",
+			data:      map[string]string{"a": "${b}", "b": "x${c}", "c": "${a}"},
+			wantError: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := Interpolate("test1", testCase.data, load)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+		})
+	}
+}
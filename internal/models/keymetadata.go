@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+type KeyInfo struct {
+	Description   string   `json:"description,omitempty"`
+	Owner         string   `json:"owner,omitempty"`
+	AllowedValues []string `json:"allowedValues,omitempty"`
+	Deprecated    string   `json:"deprecated,omitempty"`
+	Link          string   `json:"link,omitempty"`
+}
+
+// KeyMetadata documents the keys of a service config. It is versioned apart
+// from the config, so documenting a key does not create a config version.
+type KeyMetadata struct {
+	Service string             `json:"service"`
+	Version uint32             `json:"-"`
+	Keys    map[string]KeyInfo `json:"keys"`
+}
+
+func (m *KeyMetadata) UnmarshalJSON(bytes []byte) error {
+	metadata := &struct {
+		Service string             `json:"service"`
+		Keys    map[string]KeyInfo `json:"keys"`
+	}{}
+
+	err := json.Unmarshal(bytes, &metadata)
+	if err != nil {
+		return err
+	}
+
+	for k := range metadata.Keys {
+		if k == "" {
+			return fmt.Errorf("metadata key is empty")
+		}
+	}
+
+	m.Service = metadata.Service
+	m.Keys = metadata.Keys
+
+	return nil
+}
+
+// DeprecationWarnings returns a warning for every deprecated key that sc sets
+// to a value different from the one in prev.
+func (m *KeyMetadata) DeprecationWarnings(sc *ServiceConfig, prev *ServiceConfig) []string {
+	var warnings []string
+
+	keys := make([]string, 0, len(sc.Data))
+	for k := range sc.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		info, found := m.Keys[k]
+		if !found || info.Deprecated == "" {
+			continue
+		}
+		if prev != nil {
+			if v, found := prev.Data[k]; found && v == sc.Data[k] {
+				continue
+			}
+		}
+		warnings = append(warnings, fmt.Sprintf("key '%s' is deprecated: %s", k, info.Deprecated))
+	}
+
+	return warnings
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDeprecationWarnings(t *testing.T) {
+	metadata := &KeyMetadata{
+		Service: "test1",
+		Keys: map[string]KeyInfo{
+			"db.pool":  {Deprecated: "use db.pool.max"},
+			"db.host":  {Description: "database host"},
+			"log.json": {Deprecated: "logs are always JSON"},
+		},
+	}
+
+	prev := &ServiceConfig{Data: map[string]string{"db.pool": "10", "log.json": "true"}}
+
+	testTable := []struct {
+		name    string
+		sc      *ServiceConfig
+		prev    *ServiceConfig
+		expects []string
+	}{
+		{
+			name: "OK New Config",
+			sc:   &ServiceConfig{Data: map[string]string{"db.pool": "10", "db.host": "localhost"}},
+			expects: []string{
+				"key 'db.pool' is deprecated: use db.pool.max",
+			},
+		},
+		{
+			name:    "OK Unchanged Keys",
+			sc:      &ServiceConfig{Data: map[string]string{"db.pool": "10", "log.json": "true"}},
+			prev:    prev,
+			expects: nil,
+		},
+		{
+			name: "OK Changed Keys",
+			sc:   &ServiceConfig{Data: map[string]string{"db.pool": "20", "log.json": "false"}},
+			prev: prev,
+			expects: []string{
+				"key 'db.pool' is deprecated: use db.pool.max",
+				"key 'log.json' is deprecated: logs are always JSON",
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expects, metadata.DeprecationWarnings(testCase.sc, testCase.prev))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	MergeOverride = "override"
+	MergeAppend   = "append"
+	MergeDelete   = "delete"
+)
+
+// MergeLayers deep-merges the data of layers ordered from the base config to
+// the most specific one. Besides the merged data it returns the provenance of
+// every key: the service whose layer supplied its value.
+//
+// Keys are merged according to the merge strategy of the layer: "override"
+// (the default) replaces the value, "append" concatenates JSON list values
+// and "delete" removes the key together with all its nested "key.*" keys.
+func MergeLayers(layers []*ServiceConfig) (map[string]string, map[string]string, error) {
+	data := map[string]string{}
+	provenance := map[string]string{}
+
+	for _, layer := range layers {
+		for k, strategy := range layer.Spec.Merge {
+			if strategy != MergeDelete {
+				continue
+			}
+			for key := range data {
+				if key == k || strings.HasPrefix(key, k+".") {
+					delete(data, key)
+					delete(provenance, key)
+				}
+			}
+		}
+
+		for k, v := range layer.Data {
+			if layer.Spec.Merge[k] == MergeAppend {
+				appended, err := appendList(data[k], v)
+				if err != nil {
+					return nil, nil, fmt.Errorf("cannot append key '%s' of service '%s': %v", k, layer.Service, err)
+				}
+				v = appended
+			}
+			data[k] = v
+			provenance[k] = layer.Service
+		}
+	}
+
+	return data, provenance, nil
+}
+
+func appendList(base, v string) (string, error) {
+	var list, tail []string
+
+	if base != "" {
+		if err := json.Unmarshal([]byte(base), &list); err != nil {
+			return "", fmt.Errorf("base value is not a list")
+		}
+	}
+	if err := json.Unmarshal([]byte(v), &tail); err != nil {
+		return "", fmt.Errorf("value is not a list")
+	}
+
+	bytes, err := json.Marshal(append(list, tail...))
+	if err != nil {
+		return "", err
+	}
+
+	return string(bytes), nil
+}
+
+func validateMergeStrategies(merge map[string]string, data map[string]string) error {
+	for k, strategy := range merge {
+		switch strategy {
+		case MergeOverride, MergeDelete:
+		case MergeAppend:
+			var list []string
+			if err := json.Unmarshal([]byte(data[k]), &list); err != nil {
+				return fmt.Errorf("value of key '%s' merged with append is not a list", k)
+			}
+		default:
+			return fmt.Errorf("unknown merge strategy '%s' for key '%s'", strategy, k)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMergeLayers(t *testing.T) {
+	base := &ServiceConfig{
+		Service: "base",
+		Data: map[string]string{
+			"db.host":    "localhost",
+			"db.port":    "5432",
+			"log.level":  "info",
+			"hosts":      `["a","b"]`,
+			"feature.on": "false",
+		},
+	}
+
+	testTable := []struct {
+		name           string
+		layer          *ServiceConfig
+		expects        map[string]string
+		expectsOrigins map[string]string
+		wantError      bool
+	}{
+		{
+			name: "OK Override",
+			layer: &ServiceConfig{
+				Service: "prod",
+				Data:    map[string]string{"db.host": "db.prod", "region": "eu"},
+			},
+			expects: map[string]string{
+				"db.host":    "db.prod",
+				"db.port":    "5432",
+				"log.level":  "info",
+				"hosts":      `["a","b"]`,
+				"feature.on": "false",
+				"region":     "eu",
+			},
+			expectsOrigins: map[string]string{
+				"db.host":    "prod",
+				"db.port":    "base",
+				"log.level":  "base",
+				"hosts":      "base",
+				"feature.on": "base",
+				"region":     "prod",
+			},
+		},
+		{
+			name: "OK Append And Delete",
+			layer: &ServiceConfig{
+				Service: "prod",
+				Data:    map[string]string{"hosts": `["c"]`},
+				Spec: Spec{
+					Merge: map[string]string{"hosts": MergeAppend, "db": MergeDelete, "feature.on": MergeDelete},
+				},
+			},
+			expects: map[string]string{
+				"log.level": "info",
+				"hosts":     `["a","b","c"]`,
+			},
+			expectsOrigins: map[string]string{
+				"log.level": "base",
+				"hosts":     "prod",
+			},
+		},
+		{
+			name: "AppendToNotAList",
+			layer: &ServiceConfig{
+				Service: "prod",
+				Data:    map[string]string{"log.level": `["debug"]`},
+				Spec: Spec{
+					Merge: map[string]string{"log.level": MergeAppend},
+				},
+			},
+			wantError: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, origins, err := MergeLayers([]*ServiceConfig{base, testCase.layer})
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+				assert.Equal(t, testCase.expectsOrigins, origins)
+			}
+		})
+	}
+}
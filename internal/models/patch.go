@@ -0,0 +1,140 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	PatchMerge = "merge"
+	PatchJSON  = "json"
+)
+
+// MergePatch applies a JSON Merge Patch (RFC 7396) to the flat config data:
+// string values set keys and null values remove them.
+func MergePatch(data map[string]string, patch []byte) (map[string]string, error) {
+	var values map[string]*string
+	if err := json.Unmarshal(patch, &values); err != nil {
+		return nil, fmt.Errorf("merge patch is not an object of string or null values: %v", err)
+	}
+
+	patched := copyData(data)
+	for k, v := range values {
+		if v == nil {
+			delete(patched, k)
+		} else {
+			patched[k] = *v
+		}
+	}
+
+	return patched, nil
+}
+
+// ApplyJSONPatch applies the operations of a JSON Patch document (RFC 6902)
+// to the flat config data, either all of them or none. Paths point to a key
+// of the data.
+func ApplyJSONPatch(data map[string]string, patch []byte) (map[string]string, error) {
+	var operations []PatchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("JSON patch is not a list of operations: %v", err)
+	}
+
+	patched := copyData(data)
+	for i, op := range operations {
+		key, err := patchKey(op.Path)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %v", i, err)
+		}
+
+		value, isString := op.Value.(string)
+		current, found := patched[key]
+
+		switch op.Op {
+		case "add", "replace":
+			if !isString {
+				return nil, fmt.Errorf("operation %d: value of key '%s' is not a string", i, key)
+			}
+			if op.Op == "replace" && !found {
+				return nil, fmt.Errorf("operation %d: key '%s' not found", i, key)
+			}
+			patched[key] = value
+		case "remove":
+			if !found {
+				return nil, fmt.Errorf("operation %d: key '%s' not found", i, key)
+			}
+			delete(patched, key)
+		case "move", "copy":
+			from, err := patchKey(op.From)
+			if err != nil {
+				return nil, fmt.Errorf("operation %d: %v", i, err)
+			}
+			v, found := patched[from]
+			if !found {
+				return nil, fmt.Errorf("operation %d: key '%s' not found", i, from)
+			}
+			if op.Op == "move" {
+				delete(patched, from)
+			}
+			patched[key] = v
+		case "test":
+			if !found || !isString || current != value {
+				return nil, fmt.Errorf("operation %d: test of key '%s' failed", i, key)
+			}
+		default:
+			return nil, fmt.Errorf("operation %d: unknown operation '%s'", i, op.Op)
+		}
+	}
+
+	return patched, nil
+}
+
+// WithData returns the next version of the service config holding data. Keys
+// removed from the data lose their merge strategy and expiry, and changed keys
+// no longer expire.
+func (s *ServiceConfig) WithData(data map[string]string) *ServiceConfig {
+	sc := *s
+	sc.Data = data
+	sc.Spec.Expired = nil
+
+	sc.Spec.Merge = nil
+	for k, strategy := range s.Spec.Merge {
+		if _, found := data[k]; found {
+			if sc.Spec.Merge == nil {
+				sc.Spec.Merge = map[string]string{}
+			}
+			sc.Spec.Merge[k] = strategy
+		}
+	}
+
+	sc.Spec.Expires = nil
+	for k, e := range s.Spec.Expires {
+		if v, found := data[k]; found && v == s.Data[k] {
+			if sc.Spec.Expires == nil {
+				sc.Spec.Expires = map[string]Expiry{}
+			}
+			sc.Spec.Expires[k] = e
+		}
+	}
+
+	return &sc
+}
+
+func patchPath(key string) string {
+	return "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+func patchKey(path string) (string, error) {
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("invalid path '%s'", path)
+	}
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(path[1:]), nil
+}
+
+func copyData(data map[string]string) map[string]string {
+	c := make(map[string]string, len(data))
+	for k, v := range data {
+		c[k] = v
+	}
+	return c
+}
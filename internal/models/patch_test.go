@@ -0,0 +1,133 @@
+package models
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMergePatch(t *testing.T) {
+	data := map[string]string{"k1": "v1", "k2": "v2"}
+
+	testTable := []struct {
+		name      string
+		patch     string
+		expects   map[string]string
+		wantError bool
+	}{
+		{
+			name:    "OK",
+			patch:   `{"k1": null, "k2": "changed", "k3": "v3"}`,
+			expects: map[string]string{"k2": "changed", "k3": "v3"},
+		},
+		{
+			name:      "NotAString",
+			patch:     `{"k1": 1}`,
+			wantError: true,
+		},
+		{
+			name:      "NotAnObject",
+			patch:     `["k1"]`,
+			wantError: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := MergePatch(data, []byte(testCase.patch))
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+			assert.Equal(t, map[string]string{"k1": "v1", "k2": "v2"}, data)
+		})
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	data := map[string]string{"k1": "v1", "a/b": "v2"}
+
+	testTable := []struct {
+		name      string
+		patch     string
+		expects   map[string]string
+		wantError bool
+	}{
+		{
+			name: "OK",
+			patch: `[{"op": "test", "path": "/k1", "value": "v1"},
+				{"op": "replace", "path": "/a~1b", "value": "changed"},
+				{"op": "move", "from": "/k1", "path": "/k~0"},
+				{"op": "copy", "from": "/k~0", "path": "/k3"},
+				{"op": "add", "path": "/k4", "value": "v4"}]`,
+			expects: map[string]string{"a/b": "changed", "k~": "v1", "k3": "v1", "k4": "v4"},
+		},
+		{
+			name:    "OK Remove",
+			patch:   `[{"op": "remove", "path": "/k1"}]`,
+			expects: map[string]string{"a/b": "v2"},
+		},
+		{
+			name:      "TestFailed",
+			patch:     `[{"op": "remove", "path": "/k1"}, {"op": "test", "path": "/a~1b", "value": "v1"}]`,
+			wantError: true,
+		},
+		{
+			name:      "KeyNotFound",
+			patch:     `[{"op": "replace", "path": "/k9", "value": "v9"}]`,
+			wantError: true,
+		},
+		{
+			name:      "InvalidPath",
+			patch:     `[{"op": "add", "path": "k9", "value": "v9"}]`,
+			wantError: true,
+		},
+		{
+			name:      "UnknownOperation",
+			patch:     `[{"op": "increment", "path": "/k1"}]`,
+			wantError: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := ApplyJSONPatch(data, []byte(testCase.patch))
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+			assert.Equal(t, map[string]string{"k1": "v1", "a/b": "v2"}, data)
+		})
+	}
+}
+
+func TestWithData(t *testing.T) {
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	sc := &ServiceConfig{
+		Service: "test1",
+		Version: 2,
+		Data:    map[string]string{"k1": "v1", "k2": "v2", "k3": "[]"},
+		Spec: Spec{
+			Secrets: []string{"k1"},
+			Merge:   map[string]string{"k3": MergeAppend},
+			Expires: map[string]Expiry{"k1": {At: at}, "k2": {At: at}},
+			Expired: []string{"k4"},
+		},
+	}
+
+	got := sc.WithData(map[string]string{"k1": "v1", "k2": "changed"})
+	assert.Equal(t, &ServiceConfig{
+		Service: "test1",
+		Version: 2,
+		Data:    map[string]string{"k1": "v1", "k2": "changed"},
+		Spec: Spec{
+			Secrets: []string{"k1"},
+			Expires: map[string]Expiry{"k1": {At: at}},
+		},
+	}, got)
+	assert.Len(t, sc.Spec.Expires, 2)
+}
@@ -0,0 +1,83 @@
+package models
+
+import "fmt"
+
+// Rule overrides the value of a key when the read context matches the When
+// selector, e.g. {"key": "timeout", "when": "region=eu", "value": "8s"}.
+type Rule struct {
+	Key   string `json:"key"`
+	When  string `json:"when"`
+	Value string `json:"value"`
+}
+
+// LayerRules returns the rules of the layers ordered from the most specific
+// layer to the root parent. The rules a layer declares for a key are dropped
+// when a more specific layer overrides the value of that key.
+func LayerRules(layers []*ServiceConfig) []Rule {
+	var rules []Rule
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		for _, r := range layers[i].Spec.Rules {
+			if !definesKey(layers[i+1:], r.Key) {
+				rules = append(rules, r)
+			}
+		}
+	}
+
+	return rules
+}
+
+// ResolveRules returns data with the value of every key replaced by the value
+// of the first of its rules matching the context.
+func ResolveRules(data map[string]string, rules []Rule, context map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(data))
+	for k, v := range data {
+		resolved[k] = v
+	}
+
+	matched := map[string]bool{}
+	for _, r := range rules {
+		if matched[r.Key] {
+			continue
+		}
+
+		selector, err := ParseSelector(r.When)
+		if err != nil {
+			return nil, err
+		}
+
+		if selector.Matches(context) {
+			resolved[r.Key] = r.Value
+			matched[r.Key] = true
+		}
+	}
+
+	return resolved, nil
+}
+
+func validateRules(rules []Rule) error {
+	for _, r := range rules {
+		if r.Key == "" {
+			return fmt.Errorf("rule key is empty")
+		}
+
+		selector, err := ParseSelector(r.When)
+		if err != nil {
+			return err
+		} else if len(selector.requirements) == 0 {
+			return fmt.Errorf("rule for key '%s' has no condition", r.Key)
+		}
+	}
+
+	return nil
+}
+
+func definesKey(layers []*ServiceConfig, key string) bool {
+	for _, layer := range layers {
+		if _, found := layer.Data[key]; found {
+			return true
+		}
+	}
+
+	return false
+}
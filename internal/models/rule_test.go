@@ -0,0 +1,78 @@
+package models
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestResolveRules(t *testing.T) {
+	base := &ServiceConfig{
+		Service: "base",
+		Data:    map[string]string{"timeout": "5s", "retries": "3"},
+		Spec: Spec{
+			Rules: []Rule{
+				{Key: "timeout", When: "region=eu", Value: "8s"},
+				{Key: "retries", When: "region=eu", Value: "5"},
+			},
+		},
+	}
+	prod := &ServiceConfig{
+		Service: "prod",
+		Data:    map[string]string{"retries": "1"},
+		Spec: Spec{
+			Parent: "base",
+			Rules: []Rule{
+				{Key: "timeout", When: "region=eu,tier=batch", Value: "30s"},
+				{Key: "timeout", When: "region in (eu,us)", Value: "10s"},
+			},
+		},
+	}
+
+	testTable := []struct {
+		name    string
+		layers  []*ServiceConfig
+		context map[string]string
+		expects map[string]string
+	}{
+		{
+			name:    "OK No Context",
+			layers:  []*ServiceConfig{base},
+			expects: map[string]string{"timeout": "5s", "retries": "3"},
+		},
+		{
+			name:    "OK Matching Context",
+			layers:  []*ServiceConfig{base},
+			context: map[string]string{"region": "eu"},
+			expects: map[string]string{"timeout": "8s", "retries": "5"},
+		},
+		{
+			name:    "OK First Rule Wins",
+			layers:  []*ServiceConfig{base, prod},
+			context: map[string]string{"region": "eu", "tier": "batch"},
+			expects: map[string]string{"timeout": "30s", "retries": "1"},
+		},
+		{
+			name:    "OK Child Rules Before Parent Rules",
+			layers:  []*ServiceConfig{base, prod},
+			context: map[string]string{"region": "eu"},
+			expects: map[string]string{"timeout": "10s", "retries": "1"},
+		},
+		{
+			name:    "OK Parent Rules Apply To Inherited Keys",
+			layers:  []*ServiceConfig{base, prod},
+			context: map[string]string{"region": "us"},
+			expects: map[string]string{"timeout": "10s", "retries": "1"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			data, _, err := MergeLayers(testCase.layers)
+			assert.NoError(t, err)
+
+			resolved, err := ResolveRules(data, LayerRules(testCase.layers), testCase.context)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expects, resolved)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	SchemaTypeString   = "string"
+	SchemaTypeInt      = "int"
+	SchemaTypeFloat    = "float"
+	SchemaTypeBool     = "bool"
+	SchemaTypeDuration = "duration"
+)
+
+// schemaTypeWidenings lists for every type the types it can be widened to
+// without breaking values that were valid for the original type.
+var schemaTypeWidenings = map[string][]string{
+	SchemaTypeString:   {},
+	SchemaTypeInt:      {SchemaTypeFloat, SchemaTypeString},
+	SchemaTypeFloat:    {SchemaTypeString},
+	SchemaTypeBool:     {SchemaTypeString},
+	SchemaTypeDuration: {SchemaTypeString},
+}
+
+type SchemaKey struct {
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+type Schema struct {
+	Service string               `json:"service"`
+	Version uint32               `json:"-"`
+	Keys    map[string]SchemaKey `json:"keys"`
+}
+
+func (s *Schema) UnmarshalJSON(bytes []byte) error {
+	schema := &struct {
+		Service string               `json:"service"`
+		Keys    map[string]SchemaKey `json:"keys"`
+	}{}
+
+	err := json.Unmarshal(bytes, &schema)
+	if err != nil {
+		return err
+	}
+
+	for k, key := range schema.Keys {
+		if key.Type == "" {
+			key.Type = SchemaTypeString
+			schema.Keys[k] = key
+		}
+		if _, found := schemaTypeWidenings[key.Type]; !found {
+			return fmt.Errorf("unknown type '%s' for key '%s'", key.Type, k)
+		}
+	}
+
+	s.Service = schema.Service
+	s.Keys = schema.Keys
+
+	return nil
+}
+
+// Validate returns the list of problems found in data, an empty list means
+// the data conforms to the schema. Keys absent from the schema are allowed.
+// Issues never include values, since they may be secrets.
+func (s *Schema) Validate(data map[string]string) []string {
+	var issues []string
+
+	for _, k := range s.sortedKeys() {
+		key := s.Keys[k]
+		v, found := data[k]
+		if !found {
+			if key.Required {
+				issues = append(issues, fmt.Sprintf("required key '%s' is missing", k))
+			}
+			continue
+		}
+		if !isValidSchemaValue(key.Type, v) {
+			issues = append(issues, fmt.Sprintf("value of key '%s' is not of type %s", k, key.Type))
+		}
+	}
+
+	return issues
+}
+
+// CheckCompatibility returns the list of changes in s that break backward
+// compatibility with prev: removed required keys and narrowed types.
+func (s *Schema) CheckCompatibility(prev *Schema) []string {
+	var issues []string
+
+	for _, k := range prev.sortedKeys() {
+		prevKey := prev.Keys[k]
+		key, found := s.Keys[k]
+		if !found {
+			if prevKey.Required {
+				issues = append(issues, fmt.Sprintf("required key '%s' has been removed", k))
+			}
+			continue
+		}
+		if !isSchemaTypeWidening(prevKey.Type, key.Type) {
+			issues = append(issues, fmt.Sprintf("type of key '%s' narrowed from %s to %s", k, prevKey.Type, key.Type))
+		}
+	}
+
+	return issues
+}
+
+func (s *Schema) sortedKeys() []string {
+	keys := make([]string, 0, len(s.Keys))
+	for k := range s.Keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func isSchemaTypeWidening(from, to string) bool {
+	if from == to {
+		return true
+	}
+	for _, t := range schemaTypeWidenings[from] {
+		if t == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isValidSchemaValue(schemaType, v string) bool {
+	var err error
+
+	switch schemaType {
+	case SchemaTypeInt:
+		_, err = strconv.ParseInt(v, 10, 64)
+	case SchemaTypeFloat:
+		_, err = strconv.ParseFloat(v, 64)
+	case SchemaTypeBool:
+		_, err = strconv.ParseBool(v)
+	case SchemaTypeDuration:
+		_, err = time.ParseDuration(v)
+	}
+
+	return err == nil
+}
+
+// TypedValue returns the value of a key as the Go value of its schema type:
+// int64, float64 or bool. Values of other types, or that don't parse, are
+// returned as strings.
+func TypedValue(schemaType, v string) interface{} {
+	switch schemaType {
+	case SchemaTypeInt:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	case SchemaTypeFloat:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case SchemaTypeBool:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSchemaCheckCompatibility(t *testing.T) {
+	prev := &Schema{
+		Service: "test1",
+		Keys: map[string]SchemaKey{
+			"host":    {Type: SchemaTypeString, Required: true},
+			"port":    {Type: SchemaTypeInt, Required: true},
+			"debug":   {Type: SchemaTypeBool},
+			"timeout": {Type: SchemaTypeFloat},
+		},
+	}
+
+	testTable := []struct {
+		name       string
+		keys       map[string]SchemaKey
+		wantIssues int
+	}{
+		{
+			name: "OK Same Schema",
+			keys: prev.Keys,
+		},
+		{
+			name: "OK Widened Type And Removed Optional Key",
+			keys: map[string]SchemaKey{
+				"host":    {Type: SchemaTypeString, Required: true},
+				"port":    {Type: SchemaTypeFloat, Required: true},
+				"timeout": {Type: SchemaTypeString},
+			},
+		},
+		{
+			name: "RemovedRequiredKey",
+			keys: map[string]SchemaKey{
+				"port": {Type: SchemaTypeInt, Required: true},
+			},
+			wantIssues: 1,
+		},
+		{
+			name: "NarrowedType",
+			keys: map[string]SchemaKey{
+				"host":    {Type: SchemaTypeInt, Required: true},
+				"port":    {Type: SchemaTypeInt, Required: true},
+				"timeout": {Type: SchemaTypeInt},
+			},
+			wantIssues: 2,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			s := &Schema{Service: "test1", Keys: testCase.keys}
+			assert.Len(t, s.CheckCompatibility(prev), testCase.wantIssues)
+		})
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	s := &Schema{
+		Service: "test1",
+		Keys: map[string]SchemaKey{
+			"port":    {Type: SchemaTypeInt, Required: true},
+			"timeout": {Type: SchemaTypeDuration},
+		},
+	}
+
+	testTable := []struct {
+		name       string
+		data       map[string]string
+		wantIssues int
+	}{
+		{
+			name: "OK",
+			data: map[string]string{"port": "8080", "timeout": "5s", "other": "value"},
+		},
+		{
+			name:       "MissingRequiredKey",
+			data:       map[string]string{"timeout": "5s"},
+			wantIssues: 1,
+		},
+		{
+			name:       "WrongType",
+			data:       map[string]string{"port": "http", "timeout": "5"},
+			wantIssues: 2,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Len(t, s.Validate(testCase.data), testCase.wantIssues)
+		})
+	}
+}
+
+func TestSchemaValidateOmitsValues(t *testing.T) {
+	s := &Schema{
+		Service: "test1",
+		Keys: map[string]SchemaKey{
+			"password": {Type: SchemaTypeInt},
+		},
+	}
+
+	assert.Equal(t, []string{"value of key 'password' is not of type int"}, s.Validate(map[string]string{"password": "hunter2"}))
+}
+
+func TestSchemaUnmarshalJSON(t *testing.T) {
+	s := &Schema{}
+
+	err := s.UnmarshalJSON([]byte(`{"service":"test1","keys":{"host":{"required":true},"port":{"type":"int"}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, SchemaKey{Type: SchemaTypeString, Required: true}, s.Keys["host"])
+	assert.Equal(t, SchemaKey{Type: SchemaTypeInt}, s.Keys["port"])
+
+	err = s.UnmarshalJSON([]byte(`{"service":"test1","keys":{"host":{"type":"uuid"}}}`))
+	assert.Error(t, err)
+}
+
+func TestTypedValue(t *testing.T) {
+	assert.Equal(t, int64(8080), TypedValue(SchemaTypeInt, "8080"))
+	assert.Equal(t, 0.5, TypedValue(SchemaTypeFloat, "0.5"))
+	assert.Equal(t, true, TypedValue(SchemaTypeBool, "true"))
+	assert.Equal(t, "1s", TypedValue(SchemaTypeDuration, "1s"))
+	assert.Equal(t, "8080", TypedValue(SchemaTypeString, "8080"))
+	assert.Equal(t, "eighty", TypedValue(SchemaTypeInt, "eighty"))
+	assert.Equal(t, "8080", TypedValue("", "8080"))
+}
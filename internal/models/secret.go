@@ -0,0 +1,35 @@
+package models
+
+import "sort"
+
+// SecretMask replaces secret values in every output that is not meant to
+// reveal them.
+const SecretMask = "******"
+
+// SecretKeys returns the keys marked as secrets in any of the layers.
+func SecretKeys(layers []*ServiceConfig) map[string]bool {
+	keys := map[string]bool{}
+
+	for _, layer := range layers {
+		for _, k := range layer.Spec.Secrets {
+			keys[k] = true
+		}
+	}
+
+	return keys
+}
+
+// MarkSecrets marks keys as secrets in the spec keeping the list sorted and
+// free of duplicates.
+func (s *Spec) MarkSecrets(keys ...string) {
+	marked := map[string]bool{}
+	for _, k := range append(s.Secrets, keys...) {
+		marked[k] = true
+	}
+
+	s.Secrets = s.Secrets[:0]
+	for k := range marked {
+		s.Secrets = append(s.Secrets, k)
+	}
+	sort.Strings(s.Secrets)
+}
@@ -0,0 +1,185 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var labelPattern = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9_./-]{0,61}[A-Za-z0-9])?)?$`)
+
+const (
+	selectorEquals    = "="
+	selectorNotEquals = "!="
+	selectorIn        = "in"
+	selectorNotIn     = "notin"
+	selectorExists    = "exists"
+	selectorNotExists = "!"
+)
+
+type requirement struct {
+	key      string
+	operator string
+	values   []string
+}
+
+// Selector is a Kubernetes-style label selector: a comma separated list of
+// requirements that must all hold, such as "team=payments,tier!=batch",
+// "env in (prod,staging)", "pci" or "!deprecated".
+type Selector struct {
+	requirements []requirement
+}
+
+func ValidateLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if k == "" || !labelPattern.MatchString(k) {
+			return fmt.Errorf("invalid label key '%s'", k)
+		}
+		if !labelPattern.MatchString(v) {
+			return fmt.Errorf("invalid value '%s' of label '%s'", v, k)
+		}
+	}
+
+	return nil
+}
+
+func ParseSelector(selector string) (*Selector, error) {
+	s := &Selector{}
+
+	for _, term := range splitSelector(selector) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		r, err := parseRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		s.requirements = append(s.requirements, r)
+	}
+
+	return s, nil
+}
+
+func (s *Selector) Matches(labels map[string]string) bool {
+	for _, r := range s.requirements {
+		v, found := labels[r.key]
+
+		switch r.operator {
+		case selectorEquals:
+			if !found || v != r.values[0] {
+				return false
+			}
+		case selectorNotEquals:
+			if found && v == r.values[0] {
+				return false
+			}
+		case selectorIn:
+			if !found || !contains(r.values, v) {
+				return false
+			}
+		case selectorNotIn:
+			if found && contains(r.values, v) {
+				return false
+			}
+		case selectorExists:
+			if !found {
+				return false
+			}
+		case selectorNotExists:
+			if found {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// splitSelector splits the selector on commas that are not inside a set of
+// values.
+func splitSelector(selector string) []string {
+	var terms []string
+
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(terms, selector[start:])
+}
+
+func parseRequirement(term string) (requirement, error) {
+	invalid := fmt.Errorf("invalid selector requirement '%s'", term)
+
+	if strings.HasPrefix(term, "!") && !strings.Contains(term, "=") {
+		key := strings.TrimSpace(term[1:])
+		if key == "" || !labelPattern.MatchString(key) {
+			return requirement{}, invalid
+		}
+		return requirement{key: key, operator: selectorNotExists}, nil
+	}
+
+	for _, op := range []string{"!=", "==", "="} {
+		if n := strings.Index(term, op); n != -1 {
+			key, value := strings.TrimSpace(term[:n]), strings.TrimSpace(term[n+len(op):])
+			if key == "" || !labelPattern.MatchString(key) || !labelPattern.MatchString(value) {
+				return requirement{}, invalid
+			}
+			operator := selectorEquals
+			if op == "!=" {
+				operator = selectorNotEquals
+			}
+			return requirement{key: key, operator: operator, values: []string{value}}, nil
+		}
+	}
+
+	fields := strings.Fields(term)
+	if len(fields) == 1 {
+		if !labelPattern.MatchString(fields[0]) {
+			return requirement{}, invalid
+		}
+		return requirement{key: fields[0], operator: selectorExists}, nil
+	}
+
+	if len(fields) < 3 || !labelPattern.MatchString(fields[0]) || (fields[1] != selectorIn && fields[1] != selectorNotIn) {
+		return requirement{}, invalid
+	}
+
+	set := strings.TrimSpace(strings.Join(fields[2:], " "))
+	if !strings.HasPrefix(set, "(") || !strings.HasSuffix(set, ")") {
+		return requirement{}, invalid
+	}
+
+	var values []string
+	for _, v := range strings.Split(set[1:len(set)-1], ",") {
+		v = strings.TrimSpace(v)
+		if !labelPattern.MatchString(v) {
+			return requirement{}, invalid
+		}
+		values = append(values, v)
+	}
+
+	return requirement{key: fields[0], operator: fields[1], values: values}, nil
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
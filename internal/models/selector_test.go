@@ -0,0 +1,45 @@
+package models
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSelectorMatches(t *testing.T) {
+	labels := map[string]string{
+		"team": "payments",
+		"tier": "api",
+		"pci":  "true",
+	}
+
+	testTable := []struct {
+		selector  string
+		matches   bool
+		wantError bool
+	}{
+		{selector: "", matches: true},
+		{selector: "team=payments", matches: true},
+		{selector: "team==payments,tier!=batch", matches: true},
+		{selector: "team=payments,tier=batch", matches: false},
+		{selector: "tier in (api, web)", matches: true},
+		{selector: "tier notin (api,web)", matches: false},
+		{selector: "pci", matches: true},
+		{selector: "!pci", matches: false},
+		{selector: "!deprecated,owner!=bob", matches: true},
+		{selector: "team in api", wantError: true},
+		{selector: "team=pay ments", wantError: true},
+		{selector: "=payments", wantError: true},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.selector, func(t *testing.T) {
+			s, err := ParseSelector(testCase.selector)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.matches, s.Matches(labels))
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Service is an entry of the configs table: a service together with its
+// labels and annotations, which are not part of the versioned config data.
+type Service struct {
+	ID            int
+	Name          string
+	Labels        map[string]string
+	Annotations   map[string]string
+	LatestVersion uint32
+	UpdatedAt     time.Time
+}
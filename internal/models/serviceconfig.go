@@ -3,24 +3,46 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 type Data struct {
 }
 
+// Spec holds everything a config version declares besides its data.
+type Spec struct {
+	Parent  string            `json:"parent,omitempty"`
+	Merge   map[string]string `json:"merge,omitempty"`
+	Secrets []string          `json:"secrets,omitempty"`
+	Rules   []Rule            `json:"rules,omitempty"`
+	Flags   map[string]Flag   `json:"flags,omitempty"`
+	Expires map[string]Expiry `json:"expires,omitempty"`
+	Expired []string          `json:"expired,omitempty"`
+}
+
 type ServiceConfig struct {
 	ID      int
 	Service string
 	Version uint32
 	Data    map[string]string
+	Spec    Spec
+	// Message describes the change that creates the version, it is only
+	// stored by UpdateFrom.
+	Message string
 }
 
 func (s *ServiceConfig) UnmarshalJSON(bytes []byte) error {
 	config := &struct {
-		ID      int                 `json:"-"`
-		Service string              `json:"service"`
-		Version uint32              `json:"-"`
-		Data    []map[string]string `json:"data"`
+		ID      int                  `json:"-"`
+		Service string               `json:"service"`
+		Version uint32               `json:"-"`
+		Data    []map[string]string  `json:"data"`
+		Parent  string               `json:"parent"`
+		Merge   map[string]string    `json:"merge"`
+		Secrets []string             `json:"secrets"`
+		Rules   []Rule               `json:"rules"`
+		Flags   map[string]Flag      `json:"flags"`
+		Expires map[string]time.Time `json:"expires"`
 	}{}
 
 	err := json.Unmarshal(bytes, &config)
@@ -40,10 +62,45 @@ func (s *ServiceConfig) UnmarshalJSON(bytes []byte) error {
 		}
 	}
 
+	if config.Parent == config.Service && config.Parent != "" {
+		return fmt.Errorf("config of service '%s' cannot be its own parent", config.Service)
+	}
+
+	if err = validateMergeStrategies(config.Merge, m); err != nil {
+		return err
+	}
+
+	if err = validateRules(config.Rules); err != nil {
+		return err
+	}
+
+	if err = validateFlags(config.Flags); err != nil {
+		return err
+	}
+
+	var expires map[string]Expiry
+	for k, at := range config.Expires {
+		if _, found := m[k]; !found {
+			return fmt.Errorf("expiring key '%s' has no value in config data", k)
+		}
+		if expires == nil {
+			expires = map[string]Expiry{}
+		}
+		expires[k] = Expiry{At: at}
+	}
+
 	s.ID = config.ID
 	s.Service = config.Service
 	s.Version = config.Version
 	s.Data = m
+	s.Spec = Spec{
+		Parent:  config.Parent,
+		Merge:   config.Merge,
+		Secrets: config.Secrets,
+		Rules:   config.Rules,
+		Flags:   config.Flags,
+		Expires: expires,
+	}
 
 	return nil
 }
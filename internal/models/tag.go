@@ -0,0 +1,33 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var tagNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]{0,62}$`)
+
+// Tag names a config version. Immutable tags keep pointing to the version
+// they were created for, movable tags (aliases) can be repointed.
+type Tag struct {
+	Service string
+	Name    string
+	Version uint32
+	Movable bool
+}
+
+type TagMove struct {
+	Name       string
+	OldVersion uint32
+	NewVersion uint32
+	MovedAt    time.Time
+}
+
+func ValidateTagName(name string) error {
+	if !tagNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid tag name '%s'", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// placeholderPattern matches "{{name}}" parameter placeholders in template
+// values.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+type TemplateParameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type Template struct {
+	Name       string
+	Version    uint32
+	Parameters []TemplateParameter
+	Data       map[string]string
+	Spec       Spec
+}
+
+type TemplateInstance struct {
+	Service         string
+	Template        string
+	TemplateVersion uint32
+	Parameters      map[string]string
+}
+
+func (t *Template) UnmarshalJSON(bytes []byte) error {
+	template := &struct {
+		Name       string              `json:"name"`
+		Parameters []TemplateParameter `json:"parameters"`
+	}{}
+
+	err := json.Unmarshal(bytes, &template)
+	if err != nil {
+		return err
+	}
+
+	config := &ServiceConfig{}
+	if err = json.Unmarshal(bytes, config); err != nil {
+		return err
+	}
+
+	if len(config.Spec.Expires) != 0 {
+		return fmt.Errorf("template cannot declare expiring keys")
+	}
+
+	declared := map[string]bool{}
+	for _, p := range template.Parameters {
+		if p.Name == "" {
+			return fmt.Errorf("template parameter name is empty")
+		} else if declared[p.Name] {
+			return fmt.Errorf("duplicate template parameter '%s'", p.Name)
+		}
+		declared[p.Name] = true
+	}
+
+	values := []string{config.Spec.Parent}
+	for _, v := range config.Data {
+		values = append(values, v)
+	}
+	for _, r := range config.Spec.Rules {
+		values = append(values, r.Value)
+	}
+	for _, v := range values {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(v, -1) {
+			if !declared[match[1]] {
+				return fmt.Errorf("template parameter '%s' is not declared", match[1])
+			}
+		}
+	}
+
+	t.Name = template.Name
+	t.Parameters = template.Parameters
+	t.Data = config.Data
+	t.Spec = config.Spec
+
+	return nil
+}
+
+func (t *Template) MarshalJSON() ([]byte, error) {
+	keys := make([]string, 0, len(t.Data))
+	for k := range t.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	data := make([]map[string]string, 0, len(keys))
+	for _, k := range keys {
+		data = append(data, map[string]string{k: t.Data[k]})
+	}
+
+	return json.Marshal(&struct {
+		Name       string              `json:"name"`
+		Parameters []TemplateParameter `json:"parameters,omitempty"`
+		Data       []map[string]string `json:"data"`
+		Parent     string              `json:"parent,omitempty"`
+		Merge      map[string]string   `json:"merge,omitempty"`
+		Secrets    []string            `json:"secrets,omitempty"`
+		Rules      []Rule              `json:"rules,omitempty"`
+		Flags      map[string]Flag     `json:"flags,omitempty"`
+	}{
+		Name:       t.Name,
+		Parameters: t.Parameters,
+		Data:       data,
+		Parent:     t.Spec.Parent,
+		Merge:      t.Spec.Merge,
+		Secrets:    t.Spec.Secrets,
+		Rules:      t.Spec.Rules,
+		Flags:      t.Spec.Flags,
+	})
+}
+
+// SecretParameters returns the names of the parameters whose placeholders
+// appear in the values of secret keys, their values are secrets as well.
+func (t *Template) SecretParameters() map[string]bool {
+	secretKeys := map[string]bool{}
+	for _, k := range t.Spec.Secrets {
+		secretKeys[k] = true
+	}
+
+	var values []string
+	for k, v := range t.Data {
+		if secretKeys[k] {
+			values = append(values, v)
+		}
+	}
+	for _, r := range t.Spec.Rules {
+		if secretKeys[r.Key] {
+			values = append(values, r.Value)
+		}
+	}
+
+	parameters := map[string]bool{}
+	for _, v := range values {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(v, -1) {
+			parameters[match[1]] = true
+		}
+	}
+
+	return parameters
+}
+
+// Instantiate returns the config of the service created from the template
+// with the placeholders replaced by parameter values. Parameters that are not
+// given take their default value unless they are required.
+func (t *Template) Instantiate(serviceName string, parameters map[string]string) (*ServiceConfig, error) {
+	values := map[string]string{}
+	for _, p := range t.Parameters {
+		if v, found := parameters[p.Name]; found {
+			values[p.Name] = v
+		} else if p.Required {
+			return nil, fmt.Errorf("required template parameter '%s' is missing", p.Name)
+		} else {
+			values[p.Name] = p.Default
+		}
+	}
+
+	for name := range parameters {
+		if _, found := values[name]; !found {
+			return nil, fmt.Errorf("unknown template parameter '%s'", name)
+		}
+	}
+
+	replace := func(v string) string {
+		return placeholderPattern.ReplaceAllStringFunc(v, func(match string) string {
+			return values[placeholderPattern.FindStringSubmatch(match)[1]]
+		})
+	}
+
+	sc := &ServiceConfig{
+		Service: serviceName,
+		Data:    make(map[string]string, len(t.Data)),
+		Spec: Spec{
+			Parent:  replace(t.Spec.Parent),
+			Merge:   t.Spec.Merge,
+			Secrets: append([]string(nil), t.Spec.Secrets...),
+			Flags:   t.Spec.Flags,
+		},
+	}
+	for k, v := range t.Data {
+		sc.Data[k] = replace(v)
+	}
+	for _, r := range t.Spec.Rules {
+		sc.Spec.Rules = append(sc.Spec.Rules, Rule{Key: r.Key, When: r.When, Value: replace(r.Value)})
+	}
+
+	return sc, nil
+}
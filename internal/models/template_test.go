@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTemplateInstantiate(t *testing.T) {
+	template := &Template{}
+	err := json.Unmarshal([]byte(`{
+		"name": "go-service",
+		"parameters": [
+			{"name": "team", "required": true},
+			{"name": "log_level", "default": "info"}
+		],
+		"data": [
+			{"owner": "{{team}}"},
+			{"log.level": "{{ log_level }}"},
+			{"db.host": "localhost"}
+		],
+		"parent": "{{team}}/base"
+	}`), template)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testTable := []struct {
+		name       string
+		parameters map[string]string
+		expects    *ServiceConfig
+		wantError  bool
+	}{
+		{
+			name:       "OK",
+			parameters: map[string]string{"team": "payments"},
+			expects: &ServiceConfig{
+				Service: "test1",
+				Data: map[string]string{
+					"owner":     "payments",
+					"log.level": "info",
+					"db.host":   "localhost",
+				},
+				Spec: Spec{Parent: "payments/base"},
+			},
+		},
+		{
+			name:       "MissingRequiredParameter",
+			parameters: map[string]string{"log_level": "debug"},
+			wantError:  true,
+		},
+		{
+			name:       "UnknownParameter",
+			parameters: map[string]string{"team": "payments", "tier": "batch"},
+			wantError:  true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := template.Instantiate("test1", testCase.parameters)
+			if testCase.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expects, got)
+			}
+		})
+	}
+}
+
+func TestTemplateUnmarshalJSON(t *testing.T) {
+	template := &Template{}
+
+	err := json.Unmarshal([]byte(`{"name":"t","data":[{"owner":"{{team}}"}]}`), template)
+	assert.Error(t, err)
+
+	err = json.Unmarshal([]byte(`{"name":"t","parameters":[{"name":"a"},{"name":"a"}],"data":[]}`), template)
+	assert.Error(t, err)
+}
+
+func TestTemplateSecretParameters(t *testing.T) {
+	template := &Template{}
+	err := json.Unmarshal([]byte(`{
+		"name": "go-service",
+		"parameters": [
+			{"name": "team"},
+			{"name": "db_password"},
+			{"name": "staging_password"}
+		],
+		"data": [
+			{"owner": "{{team}}"},
+			{"db.password": "{{db_password}}"}
+		],
+		"secrets": ["db.password"],
+		"rules": [
+			{"key": "db.password", "when": "env=staging", "value": "{{staging_password}}"},
+			{"key": "owner", "when": "env=staging", "value": "{{team}}-staging"}
+		]
+	}`), template)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, map[string]bool{"db_password": true, "staging_password": true}, template.SecretParameters())
+}
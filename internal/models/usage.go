@@ -0,0 +1,26 @@
+package models
+
+import "strings"
+
+// Usage is the storage consumed by the services of a namespace.
+type Usage struct {
+	Namespace string
+	Services  int
+	Versions  int
+	Bytes     int64
+}
+
+// Namespace returns the namespace of the service, the part of its name before
+// the first '/'. A service without a '/' in its name is its own namespace.
+func Namespace(serviceName string) string {
+	if n := strings.IndexByte(serviceName, '/'); n != -1 {
+		return serviceName[:n]
+	}
+	return serviceName
+}
+
+// StoredSize returns the number of bytes a version of the service config
+// takes in storage.
+func StoredSize(configData []byte, specData []byte) int64 {
+	return int64(len(configData) + len(specData))
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// VersionInfo describes a stored version of a service config without its
+// data. Size and Checksum are computed over the stored data and spec,
+// RollbackOf is the version it was rolled back to and ChangesetID the
+// changeset that created it, or 0.
+type VersionInfo struct {
+	Version     uint32
+	Size        int64
+	Checksum    string
+	CreatedAt   time.Time
+	Message     string
+	RollbackOf  uint32
+	ChangesetID int
+}
+
+// Rollback republishes the ToVersion of the service config as a new version
+// and optionally moves the Tag to it.
+type Rollback struct {
+	Service   string
+	ToVersion uint32
+	Message   string
+	Tag       string
+}
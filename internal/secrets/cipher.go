@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const encryptedPrefix = "enc:v1:"
+
+// Cipher encrypts secret values with AES-256-GCM. Encryption is deterministic:
+// the nonce is derived from the plaintext, so an unchanged secret produces an
+// unchanged ciphertext and config versions stay comparable.
+type Cipher struct {
+	aead     cipher.AEAD
+	nonceKey []byte
+}
+
+func NewCipher(key string) (*Cipher, error) {
+	rawKey, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("secrets key is not valid base64: %v", err)
+	}
+	if len(rawKey) != 32 {
+		return nil, fmt.Errorf("secrets key must be 32 bytes long")
+	}
+
+	block, err := aes.NewCipher(deriveKey(rawKey, "encryption"))
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{
+		aead:     aead,
+		nonceKey: deriveKey(rawKey, "nonce"),
+	}, nil
+}
+
+func IsEncrypted(v string) bool {
+	return strings.HasPrefix(v, encryptedPrefix)
+}
+
+func (c *Cipher) Encrypt(v string) string {
+	mac := hmac.New(sha256.New, c.nonceKey)
+	mac.Write([]byte(v))
+	nonce := mac.Sum(nil)[:c.aead.NonceSize()]
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(v), nil)
+
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed)
+}
+
+func (c *Cipher) Decrypt(v string) (string, error) {
+	if !IsEncrypted(v) {
+		return "", fmt.Errorf("value is not encrypted")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return "", fmt.Errorf("encrypted value is too short")
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func deriveKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose))
+
+	return mac.Sum(nil)
+}
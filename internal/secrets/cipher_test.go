@@ -0,0 +1,39 @@
+package secrets
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const testKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+func TestCipher(t *testing.T) {
+	c, err := NewCipher(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted := c.Encrypt("p@ssw0rd")
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, encrypted, "p@ssw0rd")
+	assert.Equal(t, encrypted, c.Encrypt("p@ssw0rd"))
+	assert.NotEqual(t, encrypted, c.Encrypt("p@ssw0rd2"))
+
+	decrypted, err := c.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "p@ssw0rd", decrypted)
+
+	_, err = c.Decrypt(encrypted[:len(encrypted)-4] + "AAAA")
+	assert.Error(t, err)
+
+	_, err = c.Decrypt("p@ssw0rd")
+	assert.Error(t, err)
+}
+
+func TestNewCipherInvalidKey(t *testing.T) {
+	_, err := NewCipher("c2hvcnQ=")
+	assert.Error(t, err)
+
+	_, err = NewCipher("not base64")
+	assert.Error(t, err)
+}
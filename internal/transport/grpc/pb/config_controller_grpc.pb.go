@@ -26,6 +26,37 @@ type ConfigControllerClient interface {
 	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
 	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	RegisterSchema(ctx context.Context, in *RegisterSchemaRequest, opts ...grpc.CallOption) (*RegisterSchemaResponse, error)
+	ReadSchema(ctx context.Context, in *ReadSchemaRequest, opts ...grpc.CallOption) (*ReadSchemaResponse, error)
+	ReadSecrets(ctx context.Context, in *ReadSecretsRequest, opts ...grpc.CallOption) (*ReadSecretsResponse, error)
+	CreateTemplate(ctx context.Context, in *CreateTemplateRequest, opts ...grpc.CallOption) (*CreateTemplateResponse, error)
+	ReadTemplate(ctx context.Context, in *ReadTemplateRequest, opts ...grpc.CallOption) (*ReadTemplateResponse, error)
+	CreateFromTemplate(ctx context.Context, in *CreateFromTemplateRequest, opts ...grpc.CallOption) (*CreateFromTemplateResponse, error)
+	TemplateReport(ctx context.Context, in *TemplateReportRequest, opts ...grpc.CallOption) (*TemplateReportResponse, error)
+	SetLabels(ctx context.Context, in *SetLabelsRequest, opts ...grpc.CallOption) (*SetLabelsResponse, error)
+	ListServices(ctx context.Context, in *ListServicesRequest, opts ...grpc.CallOption) (*ListServicesResponse, error)
+	CreateTag(ctx context.Context, in *CreateTagRequest, opts ...grpc.CallOption) (*CreateTagResponse, error)
+	MoveTag(ctx context.Context, in *MoveTagRequest, opts ...grpc.CallOption) (*MoveTagResponse, error)
+	ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error)
+	CreateDraft(ctx context.Context, in *CreateDraftRequest, opts ...grpc.CallOption) (*CreateDraftResponse, error)
+	ReviewDraft(ctx context.Context, in *ReviewDraftRequest, opts ...grpc.CallOption) (*ReviewDraftResponse, error)
+	ListDrafts(ctx context.Context, in *ListDraftsRequest, opts ...grpc.CallOption) (*ListDraftsResponse, error)
+	PublishDraft(ctx context.Context, in *PublishDraftRequest, opts ...grpc.CallOption) (*PublishDraftResponse, error)
+	DiscardDraft(ctx context.Context, in *DiscardDraftRequest, opts ...grpc.CallOption) (*DiscardDraftResponse, error)
+	EvaluateFlags(ctx context.Context, in *EvaluateFlagsRequest, opts ...grpc.CallOption) (*EvaluateFlagsResponse, error)
+	ListExpiringKeys(ctx context.Context, in *ListExpiringKeysRequest, opts ...grpc.CallOption) (*ListExpiringKeysResponse, error)
+	SetKeyMetadata(ctx context.Context, in *SetKeyMetadataRequest, opts ...grpc.CallOption) (*SetKeyMetadataResponse, error)
+	ReadKeyMetadata(ctx context.Context, in *ReadKeyMetadataRequest, opts ...grpc.CallOption) (*ReadKeyMetadataResponse, error)
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ConfigController_WatchClient, error)
+	ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error)
+	Diff(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error)
+	Rollback(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*RollbackResponse, error)
+	Patch(ctx context.Context, in *PatchRequest, opts ...grpc.CallOption) (*PatchResponse, error)
+	GetKey(ctx context.Context, in *GetKeyRequest, opts ...grpc.CallOption) (*GetKeyResponse, error)
+	SetKey(ctx context.Context, in *SetKeyRequest, opts ...grpc.CallOption) (*SetKeyResponse, error)
+	DeleteKey(ctx context.Context, in *DeleteKeyRequest, opts ...grpc.CallOption) (*DeleteKeyResponse, error)
+	ApplyChangeset(ctx context.Context, in *ApplyChangesetRequest, opts ...grpc.CallOption) (*ApplyChangesetResponse, error)
 }
 
 type configControllerClient struct {
@@ -72,6 +103,308 @@ func (c *configControllerClient) Delete(ctx context.Context, in *DeleteRequest,
 	return out, nil
 }
 
+func (c *configControllerClient) RegisterSchema(ctx context.Context, in *RegisterSchemaRequest, opts ...grpc.CallOption) (*RegisterSchemaResponse, error) {
+	out := new(RegisterSchemaResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/RegisterSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) ReadSchema(ctx context.Context, in *ReadSchemaRequest, opts ...grpc.CallOption) (*ReadSchemaResponse, error) {
+	out := new(ReadSchemaResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/ReadSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) ReadSecrets(ctx context.Context, in *ReadSecretsRequest, opts ...grpc.CallOption) (*ReadSecretsResponse, error) {
+	out := new(ReadSecretsResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/ReadSecrets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) CreateTemplate(ctx context.Context, in *CreateTemplateRequest, opts ...grpc.CallOption) (*CreateTemplateResponse, error) {
+	out := new(CreateTemplateResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/CreateTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) ReadTemplate(ctx context.Context, in *ReadTemplateRequest, opts ...grpc.CallOption) (*ReadTemplateResponse, error) {
+	out := new(ReadTemplateResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/ReadTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) CreateFromTemplate(ctx context.Context, in *CreateFromTemplateRequest, opts ...grpc.CallOption) (*CreateFromTemplateResponse, error) {
+	out := new(CreateFromTemplateResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/CreateFromTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) TemplateReport(ctx context.Context, in *TemplateReportRequest, opts ...grpc.CallOption) (*TemplateReportResponse, error) {
+	out := new(TemplateReportResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/TemplateReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) SetLabels(ctx context.Context, in *SetLabelsRequest, opts ...grpc.CallOption) (*SetLabelsResponse, error) {
+	out := new(SetLabelsResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/SetLabels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) ListServices(ctx context.Context, in *ListServicesRequest, opts ...grpc.CallOption) (*ListServicesResponse, error) {
+	out := new(ListServicesResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/ListServices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) CreateTag(ctx context.Context, in *CreateTagRequest, opts ...grpc.CallOption) (*CreateTagResponse, error) {
+	out := new(CreateTagResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/CreateTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) MoveTag(ctx context.Context, in *MoveTagRequest, opts ...grpc.CallOption) (*MoveTagResponse, error) {
+	out := new(MoveTagResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/MoveTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error) {
+	out := new(ListTagsResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/ListTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) CreateDraft(ctx context.Context, in *CreateDraftRequest, opts ...grpc.CallOption) (*CreateDraftResponse, error) {
+	out := new(CreateDraftResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/CreateDraft", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) ReviewDraft(ctx context.Context, in *ReviewDraftRequest, opts ...grpc.CallOption) (*ReviewDraftResponse, error) {
+	out := new(ReviewDraftResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/ReviewDraft", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) ListDrafts(ctx context.Context, in *ListDraftsRequest, opts ...grpc.CallOption) (*ListDraftsResponse, error) {
+	out := new(ListDraftsResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/ListDrafts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) PublishDraft(ctx context.Context, in *PublishDraftRequest, opts ...grpc.CallOption) (*PublishDraftResponse, error) {
+	out := new(PublishDraftResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/PublishDraft", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) DiscardDraft(ctx context.Context, in *DiscardDraftRequest, opts ...grpc.CallOption) (*DiscardDraftResponse, error) {
+	out := new(DiscardDraftResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/DiscardDraft", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) EvaluateFlags(ctx context.Context, in *EvaluateFlagsRequest, opts ...grpc.CallOption) (*EvaluateFlagsResponse, error) {
+	out := new(EvaluateFlagsResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/EvaluateFlags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) ListExpiringKeys(ctx context.Context, in *ListExpiringKeysRequest, opts ...grpc.CallOption) (*ListExpiringKeysResponse, error) {
+	out := new(ListExpiringKeysResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/ListExpiringKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) SetKeyMetadata(ctx context.Context, in *SetKeyMetadataRequest, opts ...grpc.CallOption) (*SetKeyMetadataResponse, error) {
+	out := new(SetKeyMetadataResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/SetKeyMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) ReadKeyMetadata(ctx context.Context, in *ReadKeyMetadataRequest, opts ...grpc.CallOption) (*ReadKeyMetadataResponse, error) {
+	out := new(ReadKeyMetadataResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/ReadKeyMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error) {
+	out := new(GetUsageResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/GetUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ConfigController_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ConfigController_ServiceDesc.Streams[0], "/ConfigController/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &configControllerWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ConfigController_WatchClient interface {
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type configControllerWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *configControllerWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *configControllerClient) ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error) {
+	out := new(ListVersionsResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/ListVersions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) Diff(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error) {
+	out := new(DiffResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/Diff", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) Rollback(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*RollbackResponse, error) {
+	out := new(RollbackResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/Rollback", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) Patch(ctx context.Context, in *PatchRequest, opts ...grpc.CallOption) (*PatchResponse, error) {
+	out := new(PatchResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/Patch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) GetKey(ctx context.Context, in *GetKeyRequest, opts ...grpc.CallOption) (*GetKeyResponse, error) {
+	out := new(GetKeyResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/GetKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) SetKey(ctx context.Context, in *SetKeyRequest, opts ...grpc.CallOption) (*SetKeyResponse, error) {
+	out := new(SetKeyResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/SetKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) DeleteKey(ctx context.Context, in *DeleteKeyRequest, opts ...grpc.CallOption) (*DeleteKeyResponse, error) {
+	out := new(DeleteKeyResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/DeleteKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) ApplyChangeset(ctx context.Context, in *ApplyChangesetRequest, opts ...grpc.CallOption) (*ApplyChangesetResponse, error) {
+	out := new(ApplyChangesetResponse)
+	err := c.cc.Invoke(ctx, "/ConfigController/ApplyChangeset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ConfigControllerServer is the server API for ConfigController service.
 // All implementations must embed UnimplementedConfigControllerServer
 // for forward compatibility
@@ -80,6 +413,37 @@ type ConfigControllerServer interface {
 	Read(context.Context, *ReadRequest) (*ReadResponse, error)
 	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	RegisterSchema(context.Context, *RegisterSchemaRequest) (*RegisterSchemaResponse, error)
+	ReadSchema(context.Context, *ReadSchemaRequest) (*ReadSchemaResponse, error)
+	ReadSecrets(context.Context, *ReadSecretsRequest) (*ReadSecretsResponse, error)
+	CreateTemplate(context.Context, *CreateTemplateRequest) (*CreateTemplateResponse, error)
+	ReadTemplate(context.Context, *ReadTemplateRequest) (*ReadTemplateResponse, error)
+	CreateFromTemplate(context.Context, *CreateFromTemplateRequest) (*CreateFromTemplateResponse, error)
+	TemplateReport(context.Context, *TemplateReportRequest) (*TemplateReportResponse, error)
+	SetLabels(context.Context, *SetLabelsRequest) (*SetLabelsResponse, error)
+	ListServices(context.Context, *ListServicesRequest) (*ListServicesResponse, error)
+	CreateTag(context.Context, *CreateTagRequest) (*CreateTagResponse, error)
+	MoveTag(context.Context, *MoveTagRequest) (*MoveTagResponse, error)
+	ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error)
+	CreateDraft(context.Context, *CreateDraftRequest) (*CreateDraftResponse, error)
+	ReviewDraft(context.Context, *ReviewDraftRequest) (*ReviewDraftResponse, error)
+	ListDrafts(context.Context, *ListDraftsRequest) (*ListDraftsResponse, error)
+	PublishDraft(context.Context, *PublishDraftRequest) (*PublishDraftResponse, error)
+	DiscardDraft(context.Context, *DiscardDraftRequest) (*DiscardDraftResponse, error)
+	EvaluateFlags(context.Context, *EvaluateFlagsRequest) (*EvaluateFlagsResponse, error)
+	ListExpiringKeys(context.Context, *ListExpiringKeysRequest) (*ListExpiringKeysResponse, error)
+	SetKeyMetadata(context.Context, *SetKeyMetadataRequest) (*SetKeyMetadataResponse, error)
+	ReadKeyMetadata(context.Context, *ReadKeyMetadataRequest) (*ReadKeyMetadataResponse, error)
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error)
+	Watch(*WatchRequest, ConfigController_WatchServer) error
+	ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error)
+	Diff(context.Context, *DiffRequest) (*DiffResponse, error)
+	Rollback(context.Context, *RollbackRequest) (*RollbackResponse, error)
+	Patch(context.Context, *PatchRequest) (*PatchResponse, error)
+	GetKey(context.Context, *GetKeyRequest) (*GetKeyResponse, error)
+	SetKey(context.Context, *SetKeyRequest) (*SetKeyResponse, error)
+	DeleteKey(context.Context, *DeleteKeyRequest) (*DeleteKeyResponse, error)
+	ApplyChangeset(context.Context, *ApplyChangesetRequest) (*ApplyChangesetResponse, error)
 	mustEmbedUnimplementedConfigControllerServer()
 }
 
@@ -99,6 +463,99 @@ func (UnimplementedConfigControllerServer) Update(context.Context, *UpdateReques
 func (UnimplementedConfigControllerServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedConfigControllerServer) RegisterSchema(context.Context, *RegisterSchemaRequest) (*RegisterSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterSchema not implemented")
+}
+func (UnimplementedConfigControllerServer) ReadSchema(context.Context, *ReadSchemaRequest) (*ReadSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadSchema not implemented")
+}
+func (UnimplementedConfigControllerServer) ReadSecrets(context.Context, *ReadSecretsRequest) (*ReadSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadSecrets not implemented")
+}
+func (UnimplementedConfigControllerServer) CreateTemplate(context.Context, *CreateTemplateRequest) (*CreateTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTemplate not implemented")
+}
+func (UnimplementedConfigControllerServer) ReadTemplate(context.Context, *ReadTemplateRequest) (*ReadTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadTemplate not implemented")
+}
+func (UnimplementedConfigControllerServer) CreateFromTemplate(context.Context, *CreateFromTemplateRequest) (*CreateFromTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFromTemplate not implemented")
+}
+func (UnimplementedConfigControllerServer) TemplateReport(context.Context, *TemplateReportRequest) (*TemplateReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TemplateReport not implemented")
+}
+func (UnimplementedConfigControllerServer) SetLabels(context.Context, *SetLabelsRequest) (*SetLabelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLabels not implemented")
+}
+func (UnimplementedConfigControllerServer) ListServices(context.Context, *ListServicesRequest) (*ListServicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServices not implemented")
+}
+func (UnimplementedConfigControllerServer) CreateTag(context.Context, *CreateTagRequest) (*CreateTagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTag not implemented")
+}
+func (UnimplementedConfigControllerServer) MoveTag(context.Context, *MoveTagRequest) (*MoveTagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveTag not implemented")
+}
+func (UnimplementedConfigControllerServer) ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTags not implemented")
+}
+func (UnimplementedConfigControllerServer) CreateDraft(context.Context, *CreateDraftRequest) (*CreateDraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDraft not implemented")
+}
+func (UnimplementedConfigControllerServer) ReviewDraft(context.Context, *ReviewDraftRequest) (*ReviewDraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReviewDraft not implemented")
+}
+func (UnimplementedConfigControllerServer) ListDrafts(context.Context, *ListDraftsRequest) (*ListDraftsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDrafts not implemented")
+}
+func (UnimplementedConfigControllerServer) PublishDraft(context.Context, *PublishDraftRequest) (*PublishDraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishDraft not implemented")
+}
+func (UnimplementedConfigControllerServer) DiscardDraft(context.Context, *DiscardDraftRequest) (*DiscardDraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscardDraft not implemented")
+}
+func (UnimplementedConfigControllerServer) EvaluateFlags(context.Context, *EvaluateFlagsRequest) (*EvaluateFlagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EvaluateFlags not implemented")
+}
+func (UnimplementedConfigControllerServer) ListExpiringKeys(context.Context, *ListExpiringKeysRequest) (*ListExpiringKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListExpiringKeys not implemented")
+}
+func (UnimplementedConfigControllerServer) SetKeyMetadata(context.Context, *SetKeyMetadataRequest) (*SetKeyMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetKeyMetadata not implemented")
+}
+func (UnimplementedConfigControllerServer) ReadKeyMetadata(context.Context, *ReadKeyMetadataRequest) (*ReadKeyMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadKeyMetadata not implemented")
+}
+func (UnimplementedConfigControllerServer) GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedConfigControllerServer) Watch(*WatchRequest, ConfigController_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedConfigControllerServer) ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVersions not implemented")
+}
+func (UnimplementedConfigControllerServer) Diff(context.Context, *DiffRequest) (*DiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Diff not implemented")
+}
+func (UnimplementedConfigControllerServer) Rollback(context.Context, *RollbackRequest) (*RollbackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rollback not implemented")
+}
+func (UnimplementedConfigControllerServer) Patch(context.Context, *PatchRequest) (*PatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Patch not implemented")
+}
+func (UnimplementedConfigControllerServer) GetKey(context.Context, *GetKeyRequest) (*GetKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKey not implemented")
+}
+func (UnimplementedConfigControllerServer) SetKey(context.Context, *SetKeyRequest) (*SetKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetKey not implemented")
+}
+func (UnimplementedConfigControllerServer) DeleteKey(context.Context, *DeleteKeyRequest) (*DeleteKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteKey not implemented")
+}
+func (UnimplementedConfigControllerServer) ApplyChangeset(context.Context, *ApplyChangesetRequest) (*ApplyChangesetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyChangeset not implemented")
+}
 func (UnimplementedConfigControllerServer) mustEmbedUnimplementedConfigControllerServer() {}
 
 // UnsafeConfigControllerServer may be embedded to opt out of forward compatibility for this service.
@@ -184,30 +641,717 @@ func _ConfigController_Delete_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
-// ConfigController_ServiceDesc is the grpc.ServiceDesc for ConfigController service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var ConfigController_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "ConfigController",
-	HandlerType: (*ConfigControllerServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Create",
-			Handler:    _ConfigController_Create_Handler,
-		},
-		{
-			MethodName: "Read",
-			Handler:    _ConfigController_Read_Handler,
-		},
-		{
-			MethodName: "Update",
-			Handler:    _ConfigController_Update_Handler,
-		},
-		{
-			MethodName: "Delete",
-			Handler:    _ConfigController_Delete_Handler,
+func _ConfigController_RegisterSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).RegisterSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/RegisterSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).RegisterSchema(ctx, req.(*RegisterSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_ReadSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).ReadSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/ReadSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).ReadSchema(ctx, req.(*ReadSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_ReadSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).ReadSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/ReadSecrets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).ReadSecrets(ctx, req.(*ReadSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_CreateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).CreateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/CreateTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).CreateTemplate(ctx, req.(*CreateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_ReadTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).ReadTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/ReadTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).ReadTemplate(ctx, req.(*ReadTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_CreateFromTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFromTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).CreateFromTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/CreateFromTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).CreateFromTemplate(ctx, req.(*CreateFromTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_TemplateReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TemplateReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).TemplateReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/TemplateReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).TemplateReport(ctx, req.(*TemplateReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_SetLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLabelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).SetLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/SetLabels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).SetLabels(ctx, req.(*SetLabelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_ListServices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).ListServices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/ListServices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).ListServices(ctx, req.(*ListServicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_CreateTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).CreateTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/CreateTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).CreateTag(ctx, req.(*CreateTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_MoveTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).MoveTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/MoveTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).MoveTag(ctx, req.(*MoveTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_ListTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).ListTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/ListTags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).ListTags(ctx, req.(*ListTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_CreateDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDraftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).CreateDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/CreateDraft",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).CreateDraft(ctx, req.(*CreateDraftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_ReviewDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewDraftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).ReviewDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/ReviewDraft",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).ReviewDraft(ctx, req.(*ReviewDraftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_ListDrafts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDraftsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).ListDrafts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/ListDrafts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).ListDrafts(ctx, req.(*ListDraftsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_PublishDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishDraftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).PublishDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/PublishDraft",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).PublishDraft(ctx, req.(*PublishDraftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_DiscardDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscardDraftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).DiscardDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/DiscardDraft",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).DiscardDraft(ctx, req.(*DiscardDraftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_EvaluateFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateFlagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).EvaluateFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/EvaluateFlags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).EvaluateFlags(ctx, req.(*EvaluateFlagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_ListExpiringKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListExpiringKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).ListExpiringKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/ListExpiringKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).ListExpiringKeys(ctx, req.(*ListExpiringKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_SetKeyMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetKeyMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).SetKeyMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/SetKeyMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).SetKeyMetadata(ctx, req.(*SetKeyMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_ReadKeyMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadKeyMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).ReadKeyMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/ReadKeyMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).ReadKeyMetadata(ctx, req.(*ReadKeyMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/GetUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConfigControllerServer).Watch(m, &configControllerWatchServer{stream})
+}
+
+type ConfigController_WatchServer interface {
+	Send(*WatchEvent) error
+	grpc.ServerStream
+}
+
+type configControllerWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *configControllerWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ConfigController_ListVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).ListVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/ListVersions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).ListVersions(ctx, req.(*ListVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_Diff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).Diff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/Diff",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).Diff(ctx, req.(*DiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_Rollback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).Rollback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/Rollback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).Rollback(ctx, req.(*RollbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_Patch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).Patch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/Patch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).Patch(ctx, req.(*PatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_GetKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).GetKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/GetKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).GetKey(ctx, req.(*GetKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_SetKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).SetKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/SetKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).SetKey(ctx, req.(*SetKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_DeleteKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).DeleteKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/DeleteKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).DeleteKey(ctx, req.(*DeleteKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_ApplyChangeset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyChangesetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).ApplyChangeset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigController/ApplyChangeset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).ApplyChangeset(ctx, req.(*ApplyChangesetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ConfigController_ServiceDesc is the grpc.ServiceDesc for ConfigController service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ConfigController_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ConfigController",
+	HandlerType: (*ConfigControllerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _ConfigController_Create_Handler,
+		},
+		{
+			MethodName: "Read",
+			Handler:    _ConfigController_Read_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _ConfigController_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _ConfigController_Delete_Handler,
+		},
+		{
+			MethodName: "RegisterSchema",
+			Handler:    _ConfigController_RegisterSchema_Handler,
+		},
+		{
+			MethodName: "ReadSchema",
+			Handler:    _ConfigController_ReadSchema_Handler,
+		},
+		{
+			MethodName: "ReadSecrets",
+			Handler:    _ConfigController_ReadSecrets_Handler,
+		},
+		{
+			MethodName: "CreateTemplate",
+			Handler:    _ConfigController_CreateTemplate_Handler,
+		},
+		{
+			MethodName: "ReadTemplate",
+			Handler:    _ConfigController_ReadTemplate_Handler,
+		},
+		{
+			MethodName: "CreateFromTemplate",
+			Handler:    _ConfigController_CreateFromTemplate_Handler,
+		},
+		{
+			MethodName: "TemplateReport",
+			Handler:    _ConfigController_TemplateReport_Handler,
+		},
+		{
+			MethodName: "SetLabels",
+			Handler:    _ConfigController_SetLabels_Handler,
+		},
+		{
+			MethodName: "ListServices",
+			Handler:    _ConfigController_ListServices_Handler,
+		},
+		{
+			MethodName: "CreateTag",
+			Handler:    _ConfigController_CreateTag_Handler,
+		},
+		{
+			MethodName: "MoveTag",
+			Handler:    _ConfigController_MoveTag_Handler,
+		},
+		{
+			MethodName: "ListTags",
+			Handler:    _ConfigController_ListTags_Handler,
+		},
+		{
+			MethodName: "CreateDraft",
+			Handler:    _ConfigController_CreateDraft_Handler,
+		},
+		{
+			MethodName: "ReviewDraft",
+			Handler:    _ConfigController_ReviewDraft_Handler,
+		},
+		{
+			MethodName: "ListDrafts",
+			Handler:    _ConfigController_ListDrafts_Handler,
+		},
+		{
+			MethodName: "PublishDraft",
+			Handler:    _ConfigController_PublishDraft_Handler,
+		},
+		{
+			MethodName: "DiscardDraft",
+			Handler:    _ConfigController_DiscardDraft_Handler,
+		},
+		{
+			MethodName: "EvaluateFlags",
+			Handler:    _ConfigController_EvaluateFlags_Handler,
+		},
+		{
+			MethodName: "ListExpiringKeys",
+			Handler:    _ConfigController_ListExpiringKeys_Handler,
+		},
+		{
+			MethodName: "SetKeyMetadata",
+			Handler:    _ConfigController_SetKeyMetadata_Handler,
+		},
+		{
+			MethodName: "ReadKeyMetadata",
+			Handler:    _ConfigController_ReadKeyMetadata_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _ConfigController_GetUsage_Handler,
+		},
+		{
+			MethodName: "ListVersions",
+			Handler:    _ConfigController_ListVersions_Handler,
+		},
+		{
+			MethodName: "Diff",
+			Handler:    _ConfigController_Diff_Handler,
+		},
+		{
+			MethodName: "Rollback",
+			Handler:    _ConfigController_Rollback_Handler,
+		},
+		{
+			MethodName: "Patch",
+			Handler:    _ConfigController_Patch_Handler,
+		},
+		{
+			MethodName: "GetKey",
+			Handler:    _ConfigController_GetKey_Handler,
+		},
+		{
+			MethodName: "SetKey",
+			Handler:    _ConfigController_SetKey_Handler,
+		},
+		{
+			MethodName: "DeleteKey",
+			Handler:    _ConfigController_DeleteKey_Handler,
+		},
+		{
+			MethodName: "ApplyChangeset",
+			Handler:    _ConfigController_ApplyChangeset_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _ConfigController_Watch_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "config_controller.proto",
 }
@@ -0,0 +1,286 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.9
+// source: v2/config_controller.proto
+
+package pbv2
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ConfigControllerClient is the client API for ConfigController service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ConfigControllerClient interface {
+	CreateConfig(ctx context.Context, in *CreateConfigRequest, opts ...grpc.CallOption) (*ServiceConfig, error)
+	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*ServiceConfig, error)
+	UpdateConfig(ctx context.Context, in *UpdateConfigRequest, opts ...grpc.CallOption) (*ServiceConfig, error)
+	DeleteConfig(ctx context.Context, in *DeleteConfigRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Rollback(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*ServiceConfig, error)
+	ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error)
+}
+
+type configControllerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConfigControllerClient(cc grpc.ClientConnInterface) ConfigControllerClient {
+	return &configControllerClient{cc}
+}
+
+func (c *configControllerClient) CreateConfig(ctx context.Context, in *CreateConfigRequest, opts ...grpc.CallOption) (*ServiceConfig, error) {
+	out := new(ServiceConfig)
+	err := c.cc.Invoke(ctx, "/config_controller.v2.ConfigController/CreateConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*ServiceConfig, error) {
+	out := new(ServiceConfig)
+	err := c.cc.Invoke(ctx, "/config_controller.v2.ConfigController/GetConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) UpdateConfig(ctx context.Context, in *UpdateConfigRequest, opts ...grpc.CallOption) (*ServiceConfig, error) {
+	out := new(ServiceConfig)
+	err := c.cc.Invoke(ctx, "/config_controller.v2.ConfigController/UpdateConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) DeleteConfig(ctx context.Context, in *DeleteConfigRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/config_controller.v2.ConfigController/DeleteConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) Rollback(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*ServiceConfig, error) {
+	out := new(ServiceConfig)
+	err := c.cc.Invoke(ctx, "/config_controller.v2.ConfigController/Rollback", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configControllerClient) ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error) {
+	out := new(ListVersionsResponse)
+	err := c.cc.Invoke(ctx, "/config_controller.v2.ConfigController/ListVersions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConfigControllerServer is the server API for ConfigController service.
+// All implementations must embed UnimplementedConfigControllerServer
+// for forward compatibility
+type ConfigControllerServer interface {
+	CreateConfig(context.Context, *CreateConfigRequest) (*ServiceConfig, error)
+	GetConfig(context.Context, *GetConfigRequest) (*ServiceConfig, error)
+	UpdateConfig(context.Context, *UpdateConfigRequest) (*ServiceConfig, error)
+	DeleteConfig(context.Context, *DeleteConfigRequest) (*emptypb.Empty, error)
+	Rollback(context.Context, *RollbackRequest) (*ServiceConfig, error)
+	ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error)
+	mustEmbedUnimplementedConfigControllerServer()
+}
+
+// UnimplementedConfigControllerServer must be embedded to have forward compatible implementations.
+type UnimplementedConfigControllerServer struct {
+}
+
+func (UnimplementedConfigControllerServer) CreateConfig(context.Context, *CreateConfigRequest) (*ServiceConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateConfig not implemented")
+}
+func (UnimplementedConfigControllerServer) GetConfig(context.Context, *GetConfigRequest) (*ServiceConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
+}
+func (UnimplementedConfigControllerServer) UpdateConfig(context.Context, *UpdateConfigRequest) (*ServiceConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateConfig not implemented")
+}
+func (UnimplementedConfigControllerServer) DeleteConfig(context.Context, *DeleteConfigRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteConfig not implemented")
+}
+func (UnimplementedConfigControllerServer) Rollback(context.Context, *RollbackRequest) (*ServiceConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rollback not implemented")
+}
+func (UnimplementedConfigControllerServer) ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVersions not implemented")
+}
+func (UnimplementedConfigControllerServer) mustEmbedUnimplementedConfigControllerServer() {}
+
+// UnsafeConfigControllerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConfigControllerServer will
+// result in compilation errors.
+type UnsafeConfigControllerServer interface {
+	mustEmbedUnimplementedConfigControllerServer()
+}
+
+func RegisterConfigControllerServer(s grpc.ServiceRegistrar, srv ConfigControllerServer) {
+	s.RegisterService(&ConfigController_ServiceDesc, srv)
+}
+
+func _ConfigController_CreateConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).CreateConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/config_controller.v2.ConfigController/CreateConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).CreateConfig(ctx, req.(*CreateConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/config_controller.v2.ConfigController/GetConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).GetConfig(ctx, req.(*GetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_UpdateConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).UpdateConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/config_controller.v2.ConfigController/UpdateConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).UpdateConfig(ctx, req.(*UpdateConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_DeleteConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).DeleteConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/config_controller.v2.ConfigController/DeleteConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).DeleteConfig(ctx, req.(*DeleteConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_Rollback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).Rollback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/config_controller.v2.ConfigController/Rollback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).Rollback(ctx, req.(*RollbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigController_ListVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigControllerServer).ListVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/config_controller.v2.ConfigController/ListVersions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigControllerServer).ListVersions(ctx, req.(*ListVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ConfigController_ServiceDesc is the grpc.ServiceDesc for ConfigController service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ConfigController_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "config_controller.v2.ConfigController",
+	HandlerType: (*ConfigControllerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateConfig",
+			Handler:    _ConfigController_CreateConfig_Handler,
+		},
+		{
+			MethodName: "GetConfig",
+			Handler:    _ConfigController_GetConfig_Handler,
+		},
+		{
+			MethodName: "UpdateConfig",
+			Handler:    _ConfigController_UpdateConfig_Handler,
+		},
+		{
+			MethodName: "DeleteConfig",
+			Handler:    _ConfigController_DeleteConfig_Handler,
+		},
+		{
+			MethodName: "Rollback",
+			Handler:    _ConfigController_Rollback_Handler,
+		},
+		{
+			MethodName: "ListVersions",
+			Handler:    _ConfigController_ListVersions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "v2/config_controller.proto",
+}
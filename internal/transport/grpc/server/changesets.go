@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	"google.golang.org/grpc/status"
+)
+
+func getEmptyChangesetError() string {
+	return fmt.Sprintf("changeset has no operations")
+}
+
+func getUnknownOperationError(operation string) string {
+	return fmt.Sprintf("unknown operation '%s'", operation)
+}
+
+func getDuplicateServiceError(serviceName string) string {
+	return fmt.Sprintf("service '%s' appears in more than one operation of the changeset", serviceName)
+}
+
+func (s *gRPCServer) ApplyChangeset(ctx context.Context, req *pb.ApplyChangesetRequest) (*pb.ApplyChangesetResponse, error) {
+
+	if len(req.Operations) == 0 {
+		return nil, invalidArgument("operations", getEmptyChangesetError())
+	}
+
+	changeset := &models.Changeset{Message: req.Message}
+	resp := &pb.ApplyChangesetResponse{}
+	services := map[string]bool{}
+
+	var failed error
+	for _, op := range req.Operations {
+		result := &pb.OperationResult{Operation: op.Operation, ServiceName: op.ServiceName}
+		resp.Results = append(resp.Results, result)
+
+		csOp, err := s.decodeOperation(op, req.Message)
+		if err == nil {
+			result.ServiceName = csOp.Config.Service
+			if services[csOp.Config.Service] {
+				err = invalidArgument("serviceName", getDuplicateServiceError(csOp.Config.Service))
+			}
+			services[csOp.Config.Service] = true
+		}
+		if err != nil {
+			result.Error = errorMessage(err)
+			if failed == nil {
+				failed = err
+			}
+			continue
+		}
+
+		changeset.Operations = append(changeset.Operations, csOp)
+	}
+
+	if failed != nil {
+		return nil, changesetError(errorStatus(failed), resp)
+	}
+
+	err := database.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := database.FromContext(ctx).Changeset().Apply(changeset, func(i int, op *models.ChangesetOperation) error {
+			warnings, err := s.prepareOperation(ctx, op)
+			resp.Results[i].Warnings = warnings
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		// configs are validated against the parents the whole changeset leaves
+		for i, op := range changeset.Operations {
+			if op.Kind == models.OperationDelete {
+				continue
+			}
+			if err = s.validateServiceConfig(ctx, op.Config); err != nil {
+				return &database.OperationError{Index: i, Err: err}
+			}
+		}
+
+		return nil
+	})
+	var opErr *database.OperationError
+	if errors.As(err, &opErr) {
+		st := errorStatus(opErr.Err)
+		resp.Results[opErr.Index].Error = status.Convert(st).Message()
+		return nil, changesetError(st, resp)
+	} else if err != nil {
+		return nil, err
+	}
+
+	for i, op := range changeset.Operations {
+		if op.Kind != models.OperationDelete {
+			resp.Results[i].Version = op.Config.Version
+		}
+	}
+
+	resp.Resp = "Success"
+	resp.ChangesetId = uint32(changeset.ID)
+	resp.Applied = true
+
+	return resp, nil
+}
+
+// changesetError returns the status of the failed operation with the results
+// of all operations attached.
+func changesetError(opStatus error, resp *pb.ApplyChangesetResponse) error {
+	st := status.Convert(opStatus)
+	if withResults, err := st.WithDetails(resp); err == nil {
+		st = withResults
+	}
+	return st.Err()
+}
+
+// decodeOperation decodes an operation of a changeset, a Delete needs no
+// further preparation.
+func (s *gRPCServer) decodeOperation(op *pb.ChangesetOperation, message string) (*models.ChangesetOperation, error) {
+	switch op.Operation {
+	case models.OperationCreate, models.OperationUpdate:
+		serviceConfig, err := s.decodeServiceConfig(op.ConfData, op.Format, op.ServiceName)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Operation == models.OperationUpdate {
+			serviceConfig.Message = message
+		}
+
+		return &models.ChangesetOperation{Kind: op.Operation, Config: serviceConfig, BaseVersion: op.ExpectedVersion}, nil
+	case models.OperationDelete:
+		return &models.ChangesetOperation{
+			Kind:        models.OperationDelete,
+			Config:      &models.ServiceConfig{Service: op.ServiceName},
+			BaseVersion: op.ExpectedVersion,
+		}, nil
+	default:
+		return nil, invalidArgument("operation", getUnknownOperationError(op.Operation))
+	}
+}
+
+// prepareOperation checks an operation of a changeset the way Create and Update
+// do, against the state the operations before it left. It returns the
+// deprecation warnings of an update.
+func (s *gRPCServer) prepareOperation(ctx context.Context, op *models.ChangesetOperation) ([]string, error) {
+	serviceConfig := op.Config
+
+	switch op.Kind {
+	case models.OperationCreate:
+		if err := s.sealSecrets(ctx, serviceConfig, nil); err != nil {
+			return nil, err
+		}
+
+		return nil, s.checkQuota(ctx, serviceConfig, true)
+	case models.OperationUpdate:
+		prevServiceConfig, err := database.FromContext(ctx).ServiceConfig().Read(&models.ServiceConfig{Service: serviceConfig.Service})
+		if err != nil {
+			return nil, err
+		}
+
+		models.SetExpiryPriors(serviceConfig, prevServiceConfig)
+
+		if err = s.sealSecrets(ctx, serviceConfig, prevServiceConfig); err != nil {
+			return nil, err
+		}
+
+		if err = s.checkQuota(ctx, serviceConfig, false); err != nil {
+			return nil, err
+		}
+
+		op.BaseVersion = baseVersion(prevServiceConfig, op.BaseVersion)
+
+		return deprecationWarnings(ctx, serviceConfig, prevServiceConfig)
+	}
+
+	return nil, nil
+}
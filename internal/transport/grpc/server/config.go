@@ -1,13 +1,52 @@
 package server
 
 type Config struct {
-	Network  string `toml:"network"`
-	BindAddr string `toml:"bind_addr"`
+	Network                  string           `toml:"network"`
+	BindAddr                 string           `toml:"bind_addr"`
+	SecretsKey               string           `toml:"secrets_key"`
+	SecretsTokens            []string         `toml:"secrets_tokens"`
+	ExpiryCheckInterval      string           `toml:"expiry_check_interval"`
+	WatchHeartbeat           string           `toml:"watch_heartbeat"`
+	WatchBufferSize          int              `toml:"watch_buffer_size"`
+	IdempotencyRetention     string           `toml:"idempotency_retention"`
+	IdempotencyLease         string           `toml:"idempotency_lease"`
+	IdempotencyPurgeInterval string           `toml:"idempotency_purge_interval"`
+	Limits                   Limits           `toml:"limits"`
+	DefaultQuota             Quota            `toml:"default_quota"`
+	Quotas                   map[string]Quota `toml:"quotas"`
+}
+
+// Limits bound the size of a single service config, 0 means unlimited.
+type Limits struct {
+	MaxPayloadBytes int `toml:"max_payload_bytes"`
+	MaxKeys         int `toml:"max_keys"`
+	MaxKeyLength    int `toml:"max_key_length"`
+	MaxValueLength  int `toml:"max_value_length"`
+	MaxVersions     int `toml:"max_versions"`
+}
+
+// Quota bounds the storage of the services of a namespace, 0 means unlimited.
+type Quota struct {
+	MaxBytes    int64 `toml:"max_bytes"`
+	MaxServices int   `toml:"max_services"`
 }
 
 func NewConfig() *Config {
 	return &Config{
-		Network:  "tcp",
-		BindAddr: ":8080",
+		Network:                  "tcp",
+		BindAddr:                 ":8080",
+		ExpiryCheckInterval:      "1m",
+		WatchHeartbeat:           "15s",
+		WatchBufferSize:          64,
+		IdempotencyRetention:     "24h",
+		IdempotencyLease:         "30s",
+		IdempotencyPurgeInterval: "1h",
+		Limits: Limits{
+			MaxPayloadBytes: 1 << 20,
+			MaxKeys:         10000,
+			MaxKeyLength:    256,
+			MaxValueLength:  64 << 10,
+			MaxVersions:     1000,
+		},
 	}
 }
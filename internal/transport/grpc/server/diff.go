@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+)
+
+const (
+	diffModeUnified   = "unified"
+	diffModeJSONPatch = "jsonPatch"
+)
+
+func getUnknownDiffModeError(mode string) string {
+	return fmt.Sprintf("unknown diff mode '%s'", mode)
+}
+
+func (s *gRPCServer) Diff(ctx context.Context, req *pb.DiffRequest) (*pb.DiffResponse, error) {
+
+	if req.Mode != "" && req.Mode != diffModeUnified && req.Mode != diffModeJSONPatch {
+		return nil, invalidArgument("mode", getUnknownDiffModeError(req.Mode))
+	}
+
+	toServiceName := req.ToServiceName
+	if toServiceName == "" {
+		toServiceName = req.ServiceName
+	}
+
+	from, err := readVersion(ctx, req.ServiceName, req.FromTag, req.FromVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := readVersion(ctx, toServiceName, req.ToTag, req.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// secrets are masked before references are resolved, as Read does, and
+	// compared by their sealed values since encryption is deterministic
+	opts := renderOptions{raw: req.Raw, context: req.Context, secrets: secretsMasked}
+	fromRC, err := s.renderSealedSecrets(ctx, from, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	toRC, err := s.renderSealedSecrets(ctx, to, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := models.DiffData(fromRC.data, toRC.data)
+	maskChanges(changes, fromRC.secrets, toRC.secrets)
+	for _, keys := range [][]string{fromRC.secrets, toRC.secrets} {
+		for _, k := range keys {
+			if _, found := fromRC.data[k]; found {
+				fromRC.data[k] = models.SecretMask
+			}
+		}
+	}
+
+	resp := &pb.DiffResponse{
+		Resp:        "Success",
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Changes:     toKeyChanges(changes),
+	}
+
+	switch req.Mode {
+	case diffModeUnified:
+		resp.Unified = models.UnifiedDiff(
+			fmt.Sprintf("%s@%d", from.Service, from.Version),
+			fmt.Sprintf("%s@%d", to.Service, to.Version),
+			fromRC.data, changes)
+	case diffModeJSONPatch:
+		patch, err := json.Marshal(models.JSONPatch(changes))
+		if err != nil {
+			return nil, err
+		}
+		resp.JsonPatch = string(patch)
+	}
+
+	return resp, nil
+}
+
+// renderSealedSecrets renders the service config with opts and puts back the
+// sealed values of the secret keys.
+func (s *gRPCServer) renderSealedSecrets(ctx context.Context, sc *models.ServiceConfig, opts renderOptions) (*renderedConfig, error) {
+	rc, err := s.render(ctx, sc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.secrets = secretsSealed
+	sealed, err := s.render(ctx, sc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range rc.secrets {
+		rc.data[k] = sealed.data[k]
+	}
+
+	return rc, nil
+}
+
+func readVersion(ctx context.Context, serviceName string, tagName string, version uint32) (*models.ServiceConfig, error) {
+	version, err := resolveTag(ctx, serviceName, tagName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	screp := database.FromContext(ctx).ServiceConfig()
+	return screp.Read(&models.ServiceConfig{
+		Service: serviceName,
+		Version: version,
+	})
+}
+
+// maskChanges masks the values of the changed secret keys, a key is masked
+// when it is a secret on either side.
+func maskChanges(changes []models.KeyChange, secretKeys ...[]string) {
+	secret := map[string]bool{}
+	for _, keys := range secretKeys {
+		for _, k := range keys {
+			secret[k] = true
+		}
+	}
+
+	for i, change := range changes {
+		if !secret[change.Key] {
+			continue
+		}
+		if change.OldValue != "" {
+			changes[i].OldValue = models.SecretMask
+		}
+		if change.NewValue != "" {
+			changes[i].NewValue = models.SecretMask
+		}
+	}
+}
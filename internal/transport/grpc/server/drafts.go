@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	"time"
+)
+
+func (s *gRPCServer) CreateDraft(ctx context.Context, req *pb.CreateDraftRequest) (*pb.CreateDraftResponse, error) {
+	serviceConfig, err := s.decodeServiceConfig(req.ConfData, req.Format, req.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	screp := database.FromContext(ctx).ServiceConfig()
+	prevServiceConfig, err := screp.Read(&models.ServiceConfig{Service: serviceConfig.Service})
+	if err != nil {
+		return nil, err
+	}
+
+	models.SetExpiryPriors(serviceConfig, prevServiceConfig)
+
+	if err = s.sealSecrets(ctx, serviceConfig, prevServiceConfig); err != nil {
+		return nil, err
+	}
+
+	if err = s.validateServiceConfig(ctx, serviceConfig); err != nil {
+		return nil, err
+	}
+
+	warnings, err := deprecationWarnings(ctx, serviceConfig, prevServiceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	drep := database.FromContext(ctx).Draft()
+	draft, err := drep.Create(&models.Draft{Config: serviceConfig})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateDraftResponse{Resp: "Success", Draft: toDraftInfo(draft), Warnings: warnings}, nil
+}
+
+func (s *gRPCServer) ReviewDraft(ctx context.Context, req *pb.ReviewDraftRequest) (*pb.ReviewDraftResponse, error) {
+
+	drep := database.FromContext(ctx).Draft()
+	draft, err := drep.Read(&models.Draft{
+		ID:     int(req.DraftId),
+		Config: &models.ServiceConfig{Service: req.ServiceName},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	screp := database.FromContext(ctx).ServiceConfig()
+	published, err := screp.Read(&models.ServiceConfig{Service: req.ServiceName})
+	if err != nil {
+		return nil, err
+	}
+
+	draftRC, err := s.render(ctx, draft.Config, renderOptions{secrets: secretsMasked})
+	if err != nil {
+		return nil, err
+	}
+
+	publishedRC, err := s.render(ctx, published, renderOptions{secrets: secretsMasked})
+	if err != nil {
+		return nil, err
+	}
+
+	configData, err := json.Marshal(draftRC.data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ReviewDraftResponse{
+		Resp:          "Success",
+		Draft:         toDraftInfo(draft),
+		ConfData:      string(configData),
+		LatestVersion: published.Version,
+		Outdated:      draft.BaseVersion != published.Version,
+		Changes:       toKeyChanges(models.DiffData(publishedRC.data, draftRC.data)),
+	}, nil
+}
+
+func (s *gRPCServer) ListDrafts(ctx context.Context, req *pb.ListDraftsRequest) (*pb.ListDraftsResponse, error) {
+
+	drep := database.FromContext(ctx).Draft()
+	drafts, err := drep.List(req.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListDraftsResponse{Resp: "Success"}
+	for _, draft := range drafts {
+		resp.Drafts = append(resp.Drafts, toDraftInfo(draft))
+	}
+
+	return resp, nil
+}
+
+func (s *gRPCServer) PublishDraft(ctx context.Context, req *pb.PublishDraftRequest) (*pb.PublishDraftResponse, error) {
+
+	drep := database.FromContext(ctx).Draft()
+	draft, err := drep.Read(&models.Draft{
+		ID:     int(req.DraftId),
+		Config: &models.ServiceConfig{Service: req.ServiceName},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.validateServiceConfig(ctx, draft.Config); err != nil {
+		return nil, err
+	}
+
+	var serviceConfig *models.ServiceConfig
+	err = s.withQuota(ctx, draft.Config, false, func(psql *database.PostgreSQL) error {
+		serviceConfig, err = psql.Draft().Publish(draft)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.PublishDraftResponse{Resp: "Success", Version: serviceConfig.Version}, nil
+}
+
+func (s *gRPCServer) DiscardDraft(ctx context.Context, req *pb.DiscardDraftRequest) (*pb.DiscardDraftResponse, error) {
+
+	drep := database.FromContext(ctx).Draft()
+	_, err := drep.Discard(&models.Draft{
+		ID:     int(req.DraftId),
+		Config: &models.ServiceConfig{Service: req.ServiceName},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.DiscardDraftResponse{Resp: "Success"}, nil
+}
+
+func toDraftInfo(draft *models.Draft) *pb.DraftInfo {
+	return &pb.DraftInfo{
+		DraftId:     uint32(draft.ID),
+		BaseVersion: draft.BaseVersion,
+		CreatedAt:   draft.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toKeyChanges(changes []models.KeyChange) []*pb.KeyChange {
+	var keyChanges []*pb.KeyChange
+
+	for _, change := range changes {
+		keyChanges = append(keyChanges, &pb.KeyChange{
+			Key:      change.Key,
+			Kind:     change.Kind,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		})
+	}
+
+	return keyChanges
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/lib/pq"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"net"
+	"strconv"
+)
+
+const errorDomain = "config-controller"
+
+const (
+	preconditionIncompatibleSchema = "INCOMPATIBLE_SCHEMA"
+	preconditionSecretsKey         = "SECRETS_KEY_NOT_CONFIGURED"
+)
+
+const uniqueViolation = "23505"
+
+func getConcurrentWriteError() string {
+	return "request conflicted with a concurrent write, retry it"
+}
+
+// errorStatus converts the typed errors of the database package into gRPC
+// statuses with details, unique violations of concurrent writes into Aborted
+// and context errors into Canceled and DeadlineExceeded. Any other error is
+// logged and hidden behind an Internal status, errors caused by clients are
+// statuses already.
+func errorStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var notFoundErr *database.NotFoundError
+	var alreadyExistsErr *database.AlreadyExistsError
+	var preconditionErr *database.PreconditionError
+	var invalidArgumentErr *database.InvalidArgumentError
+
+	switch {
+	case errors.As(err, &notFoundErr):
+		return notFound(notFoundErr.Resource, notFoundErr.Name, notFoundErr.Msg)
+	case errors.As(err, &alreadyExistsErr):
+		return withDetails(status.New(codes.AlreadyExists, alreadyExistsErr.Msg), &errdetails.ResourceInfo{
+			ResourceType: alreadyExistsErr.Resource,
+			ResourceName: alreadyExistsErr.Name,
+			Description:  alreadyExistsErr.Msg,
+		})
+	case errors.As(err, &preconditionErr):
+		return failedPrecondition(preconditionErr.Type, preconditionErr.Subject, preconditionErr.CurrentVersion, preconditionErr.Msg)
+	case errors.As(err, &invalidArgumentErr):
+		return invalidArgument(invalidArgumentErr.Field, invalidArgumentErr.Msg)
+	case isUniqueViolation(err):
+		return status.Error(codes.Aborted, getConcurrentWriteError())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		log.Printf("internal error: %v", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// errorMessage returns the message of the error as it is sent to clients.
+func errorMessage(err error) string {
+	return status.Convert(errorStatus(err)).Message()
+}
+
+// asInvalidArgument returns err as an InvalidArgument status for field, unless
+// it comes from the database or its driver.
+func asInvalidArgument(field string, err error) error {
+	var notFoundErr *database.NotFoundError
+	var alreadyExistsErr *database.AlreadyExistsError
+	var preconditionErr *database.PreconditionError
+	var invalidArgumentErr *database.InvalidArgumentError
+
+	if errors.As(err, &notFoundErr) || errors.As(err, &alreadyExistsErr) ||
+		errors.As(err, &preconditionErr) || errors.As(err, &invalidArgumentErr) || isInternalError(err) {
+		return err
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return invalidArgument(field, err.Error())
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+func isInternalError(err error) bool {
+	var pqErr *pq.Error
+	var netErr net.Error
+
+	return errors.As(err, &pqErr) ||
+		errors.As(err, &netErr) ||
+		errors.Is(err, sql.ErrNoRows) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, sql.ErrTxDone) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+func notFound(resource string, name string, msg string) error {
+	return withDetails(status.New(codes.NotFound, msg), &errdetails.ResourceInfo{
+		ResourceType: resource,
+		ResourceName: name,
+		Description:  msg,
+	})
+}
+
+func invalidArgument(field string, msg string) error {
+	return withDetails(status.New(codes.InvalidArgument, msg), &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: msg},
+		},
+	})
+}
+
+// failedPrecondition returns a FailedPrecondition status, the current version
+// of the subject is sent in the error info when it is set.
+func failedPrecondition(violation string, subject string, currentVersion uint32, msg string) error {
+	info := &errdetails.ErrorInfo{Reason: violation, Domain: errorDomain}
+	if currentVersion != 0 {
+		info.Metadata = map[string]string{"currentVersion": strconv.FormatUint(uint64(currentVersion), 10)}
+	}
+
+	return withDetails(status.New(codes.FailedPrecondition, msg), info, &errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{
+			{Type: violation, Subject: subject, Description: msg},
+		},
+	})
+}
+
+// withDetails returns the status with the details attached, or without them
+// if they can't be encoded.
+func withDetails(st *status.Status, details ...proto.Message) error {
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+func unaryErrorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return resp, nil
+}
+
+func streamErrorInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		return errorStatus(err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	"time"
+)
+
+const defaultExpiringWithin = 24 * time.Hour
+
+func (s *gRPCServer) ListExpiringKeys(ctx context.Context, req *pb.ListExpiringKeysRequest) (*pb.ListExpiringKeysResponse, error) {
+
+	within := defaultExpiringWithin
+	if req.Within != "" {
+		var err error
+		if within, err = time.ParseDuration(req.Within); err != nil {
+			return nil, invalidArgument("within", err.Error())
+		}
+	}
+
+	screp := database.FromContext(ctx).ServiceConfig()
+	configs, err := screp.ReadExpiring(req.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*pb.ExpiringKey
+	for _, sc := range configs {
+		for _, k := range sc.ExpiringKeys(time.Now().Add(within)) {
+			keys = append(keys, &pb.ExpiringKey{
+				ServiceName: k.Service,
+				Version:     k.Version,
+				Key:         k.Key,
+				ExpiresAt:   k.At.Format(time.RFC3339),
+				HasPrior:    k.HasPrior,
+			})
+		}
+	}
+
+	return &pb.ListExpiringKeysResponse{Resp: "Success", Keys: keys}, nil
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+)
+
+func getFlagNotFoundError(serviceName string, flagName string) string {
+	return fmt.Sprintf("flag '%s' for '%s' service not found", flagName, serviceName)
+}
+
+func (s *gRPCServer) EvaluateFlags(ctx context.Context, req *pb.EvaluateFlagsRequest) (*pb.EvaluateFlagsResponse, error) {
+
+	version, err := resolveTag(ctx, req.ServiceName, req.Tag, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	screp := database.FromContext(ctx).ServiceConfig()
+	serviceConfig, err := screp.Read(&models.ServiceConfig{
+		Service: req.ServiceName,
+		Version: version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	layers, err := resolveLayers(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	layerFlags := models.LayerFlags(layers)
+	for _, name := range req.Flags {
+		if _, found := layerFlags[name]; !found {
+			return nil, notFound("flag", name, getFlagNotFoundError(req.ServiceName, name))
+		}
+	}
+
+	evaluated, err := models.EvaluateFlags(layerFlags, req.Flags, req.Identifier, req.Context)
+	if err != nil {
+		return nil, invalidArgument("flags", err.Error())
+	}
+
+	flags := make([]*pb.EvaluatedFlag, 0, len(evaluated))
+	for _, f := range evaluated {
+		flags = append(flags, &pb.EvaluatedFlag{
+			Name:   f.Name,
+			Value:  f.Value,
+			Reason: f.Reason,
+		})
+	}
+
+	return &pb.EvaluateFlagsResponse{Resp: "Success", Flags: flags}, nil
+}
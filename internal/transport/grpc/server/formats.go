@@ -0,0 +1,45 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/formats"
+	"github.com/wphylici/contest-cloud/internal/models"
+)
+
+func getServiceNameMismatchError(serviceName string, documentServiceName string) string {
+	return fmt.Sprintf("service name '%s' does not match service '%s' of the config", serviceName, documentServiceName)
+}
+
+// decodeServiceConfig parses the config sent by a client in the given format
+// and checks it against the size limits. The service name of the request is
+// required by formats that cannot carry it and must match the one of the
+// config otherwise.
+func (s *gRPCServer) decodeServiceConfig(confData string, format string, serviceName string) (*models.ServiceConfig, error) {
+	if err := s.checkPayload(confData); err != nil {
+		return nil, err
+	}
+
+	document, err := formats.ToJSON(format, []byte(confData))
+	if err != nil {
+		return nil, invalidArgument("confData", err.Error())
+	}
+
+	serviceConfig := &models.ServiceConfig{}
+	if err = json.Unmarshal(document, &serviceConfig); err != nil {
+		return nil, invalidArgument("confData", err.Error())
+	}
+
+	if serviceName != "" {
+		if serviceConfig.Service != "" && serviceConfig.Service != serviceName {
+			return nil, invalidArgument("serviceName", getServiceNameMismatchError(serviceName, serviceConfig.Service))
+		}
+		serviceConfig.Service = serviceName
+	}
+
+	if err = s.checkLimits(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	return serviceConfig, nil
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	pbv2 "github.com/wphylici/contest-cloud/internal/transport/grpc/pb/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const idempotencyKeyHeader = "idempotency-key"
+
+func getIdempotencyKeyReusedError(key string) string {
+	return fmt.Sprintf("idempotency key '%s' was used for a different request", key)
+}
+
+func getIdempotencyKeyInProgressError(key string) string {
+	return fmt.Sprintf("request with idempotency key '%s' is in progress", key)
+}
+
+// idempotentMethods are the mutating methods that accept an idempotency key.
+var idempotentMethods = map[string]bool{}
+
+func init() {
+	addMethods(idempotentMethods, pb.ConfigController_ServiceDesc.ServiceName,
+		"Create", "Update", "Delete", "RegisterSchema", "CreateTemplate", "CreateFromTemplate",
+		"SetLabels", "CreateTag", "MoveTag", "CreateDraft", "PublishDraft", "DiscardDraft",
+		"SetKeyMetadata", "Rollback", "Patch", "SetKey", "DeleteKey", "ApplyChangeset")
+	addMethods(idempotentMethods, pbv2.ConfigController_ServiceDesc.ServiceName,
+		"CreateConfig", "UpdateConfig", "DeleteConfig", "Rollback")
+}
+
+func addMethods(fullMethods map[string]bool, serviceName string, methods ...string) {
+	for _, method := range methods {
+		fullMethods["/"+serviceName+"/"+method] = true
+	}
+}
+
+// unaryIdempotencyInterceptor executes a mutating request sent with an
+// idempotency key once. The request runs in a transaction that holds the key
+// for at most the lease and stores it with the response, retries with the
+// same key and request get the stored response.
+func (s *gRPCServer) unaryIdempotencyInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	key := idempotencyKey(ctx)
+	if key == "" || !idempotentMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	hash, err := requestHash(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.idempotencyLease)
+	defer cancel()
+
+	psql, err := database.Psql.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer psql.Rollback()
+
+	irep := psql.Idempotency()
+	k := &models.IdempotencyKey{Key: key, Method: info.FullMethod, RequestHash: hash}
+	if locked, err := irep.Lock(k); err != nil {
+		return nil, err
+	} else if !locked {
+		return nil, status.Error(codes.Aborted, getIdempotencyKeyInProgressError(key))
+	}
+
+	stored, err := irep.Read(k, s.idempotencyRetention)
+	if err != nil {
+		return nil, err
+	} else if stored != nil {
+		return storedResponse(k, stored)
+	}
+
+	resp, err := handler(database.NewContext(ctx, psql), req)
+	if err != nil {
+		// the driver reports a query canceled by the lease as its own error
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	response, err := anypb.New(resp.(proto.Message))
+	if err != nil {
+		return nil, err
+	}
+	if k.Response, err = proto.Marshal(response); err != nil {
+		return nil, err
+	}
+
+	if err = irep.Create(k); err != nil {
+		return nil, err
+	}
+	if err = psql.Commit(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func idempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(idempotencyKeyHeader); len(values) != 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func requestHash(req interface{}) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req.(proto.Message))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func storedResponse(k *models.IdempotencyKey, stored *models.IdempotencyKey) (interface{}, error) {
+	if stored.Method != k.Method || stored.RequestHash != k.RequestHash {
+		return nil, invalidArgument(idempotencyKeyHeader, getIdempotencyKeyReusedError(k.Key))
+	}
+	response := &anypb.Any{}
+	if err := proto.Unmarshal(stored.Response, response); err != nil {
+		return nil, err
+	}
+	return response.UnmarshalNew()
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestUnaryIdempotencyInterceptor(t *testing.T) {
+	dbmock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbmock.Close()
+
+	psql := database.Psql
+	database.Psql = database.NewFromDB(dbmock)
+	defer func() { database.Psql = psql }()
+
+	s := &gRPCServer{idempotencyRetention: 24 * time.Hour, idempotencyLease: time.Minute}
+	info := &grpc.UnaryServerInfo{FullMethod: "/" + pb.ConfigController_ServiceDesc.ServiceName + "/Create"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, "deploy-42"))
+
+	req := &pb.CreateRequest{ServiceName: "test1", ConfData: `{"k1":"v1"}`}
+	hash, err := requestHash(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storedResp, err := anypb.New(&pb.CreateResponse{Resp: "Success"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := proto.Marshal(storedResp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockQuery := regexp.QuoteMeta("SELECT pg_try_advisory_xact_lock(hashtextextended($1, 0))")
+	readQuery := regexp.QuoteMeta("SELECT method, request_hash, response, created_at FROM idempotency_keys " +
+		"WHERE (key=$1) AND (created_at >= now() - $2 * interval '1 second')")
+	createQuery := regexp.QuoteMeta("INSERT INTO idempotency_keys (key, method, request_hash, response) VALUES ($1, $2, $3, $4) " +
+		"ON CONFLICT (key) DO UPDATE SET method=EXCLUDED.method, request_hash=EXCLUDED.request_hash, " +
+		"response=EXCLUDED.response, created_at=now()")
+
+	expectLock := func(locked bool) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).
+			WithArgs("deploy-42").WillReturnRows(mock.NewRows([]string{"locked"}).AddRow(locked))
+	}
+	expectStored := func(requestHash string) {
+		rows := mock.NewRows([]string{"method", "request_hash", "response", "created_at"}).
+			AddRow(info.FullMethod, requestHash, response, time.Now())
+		mock.ExpectQuery(readQuery).
+			WithArgs("deploy-42", 86400).WillReturnRows(rows)
+	}
+
+	testTable := []struct {
+		name          string
+		mockBehavior  func()
+		expects       proto.Message
+		expectHandled bool
+		wantCode      codes.Code
+	}{
+		{
+			name: "Executed",
+			mockBehavior: func() {
+				expectLock(true)
+				mock.ExpectQuery(readQuery).
+					WithArgs("deploy-42", 86400).WillReturnRows(mock.NewRows([]string{"method", "request_hash", "response", "created_at"}))
+				mock.ExpectQuery(createQuery).
+					WithArgs("deploy-42", info.FullMethod, hash, response).WillReturnRows(&sqlmock.Rows{})
+				mock.ExpectCommit()
+			},
+			expects:       &pb.CreateResponse{Resp: "Success"},
+			expectHandled: true,
+		},
+		{
+			name: "Replayed",
+			mockBehavior: func() {
+				expectLock(true)
+				expectStored(hash)
+				mock.ExpectRollback()
+			},
+			expects: &pb.CreateResponse{Resp: "Success"},
+		},
+		{
+			name: "KeyReused",
+			mockBehavior: func() {
+				expectLock(true)
+				expectStored("other")
+				mock.ExpectRollback()
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "InProgress",
+			mockBehavior: func() {
+				expectLock(false)
+				mock.ExpectRollback()
+			},
+			wantCode: codes.Aborted,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			handled := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handled = true
+				if database.FromContext(ctx) == database.Psql {
+					t.Error("handler runs outside the request transaction")
+				}
+				return &pb.CreateResponse{Resp: "Success"}, nil
+			}
+
+			got, err := s.unaryIdempotencyInterceptor(ctx, req, info, handler)
+			if testCase.wantCode != codes.OK {
+				assert.Equal(t, testCase.wantCode, status.Code(err))
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, proto.Equal(testCase.expects, got.(proto.Message)))
+			}
+			assert.Equal(t, testCase.expectHandled, handled)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+)
+
+func getKeyNotFoundError(serviceName string, key string) string {
+	return fmt.Sprintf("key '%s' not found in config of service '%s'", key, serviceName)
+}
+
+func getEmptyKeyError() string {
+	return fmt.Sprintf("key must not be empty")
+}
+
+func (s *gRPCServer) GetKey(ctx context.Context, req *pb.GetKeyRequest) (*pb.GetKeyResponse, error) {
+
+	serviceConfig, err := readVersion(ctx, req.ServiceName, req.Tag, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := s.render(ctx, serviceConfig, renderOptions{context: req.Context, secrets: secretsMasked})
+	if err != nil {
+		return nil, err
+	}
+
+	value, found := rc.data[req.Key]
+	if !found {
+		return nil, notFound("key", req.Key, getKeyNotFoundError(req.ServiceName, req.Key))
+	}
+
+	resp := &pb.GetKeyResponse{
+		Resp:       "Success",
+		Value:      value,
+		Version:    serviceConfig.Version,
+		Provenance: rc.provenance[req.Key],
+	}
+	for _, k := range rc.secrets {
+		if k == req.Key {
+			resp.Secret = true
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *gRPCServer) SetKey(ctx context.Context, req *pb.SetKeyRequest) (*pb.SetKeyResponse, error) {
+
+	if req.Key == "" {
+		return nil, invalidArgument("key", getEmptyKeyError())
+	}
+
+	prevServiceConfig, err := database.FromContext(ctx).ServiceConfig().Read(&models.ServiceConfig{Service: req.ServiceName})
+	if err != nil {
+		return nil, err
+	}
+
+	data := maskedData(prevServiceConfig)
+	prevData := maskedData(prevServiceConfig)
+	data[req.Key] = req.Value
+
+	changes := models.DiffData(prevData, data)
+	maskChanges(changes, prevServiceConfig.Spec.Secrets)
+
+	serviceConfig, warnings, err := s.writeData(ctx, prevServiceConfig, data,
+		baseVersion(prevServiceConfig, req.ExpectedVersion), req.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.SetKeyResponse{Resp: "Success", Version: serviceConfig.Version, Warnings: warnings}
+	if keyChanges := toKeyChanges(changes); len(keyChanges) != 0 {
+		resp.Change = keyChanges[0]
+	}
+
+	return resp, nil
+}
+
+func (s *gRPCServer) DeleteKey(ctx context.Context, req *pb.DeleteKeyRequest) (*pb.DeleteKeyResponse, error) {
+
+	prevServiceConfig, err := database.FromContext(ctx).ServiceConfig().Read(&models.ServiceConfig{Service: req.ServiceName})
+	if err != nil {
+		return nil, err
+	}
+
+	data := maskedData(prevServiceConfig)
+	value, found := data[req.Key]
+	if !found {
+		return nil, notFound("key", req.Key, getKeyNotFoundError(req.ServiceName, req.Key))
+	}
+	delete(data, req.Key)
+
+	serviceConfig, _, err := s.writeData(ctx, prevServiceConfig, data,
+		baseVersion(prevServiceConfig, req.ExpectedVersion), req.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteKeyResponse{
+		Resp:    "Success",
+		Version: serviceConfig.Version,
+		Change:  &pb.KeyChange{Key: req.Key, Kind: models.KeyRemoved, OldValue: value},
+	}, nil
+}
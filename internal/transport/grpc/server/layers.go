@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+)
+
+func getParentCycleError(serviceName string) string {
+	return fmt.Sprintf("config of service '%s' is part of a parent cycle", serviceName)
+}
+
+// resolveLayers returns the layers of the service config ordered from its
+// root parent to the config itself. Parents are always taken in their latest
+// version.
+func resolveLayers(ctx context.Context, sc *models.ServiceConfig) ([]*models.ServiceConfig, error) {
+	layers := []*models.ServiceConfig{sc}
+	visited := map[string]bool{sc.Service: true}
+
+	screp := database.FromContext(ctx).ServiceConfig()
+	for parent := sc.Spec.Parent; parent != ""; {
+		if visited[parent] {
+			return nil, invalidArgument("parent", getParentCycleError(parent))
+		}
+		visited[parent] = true
+
+		layer, err := screp.Read(&models.ServiceConfig{Service: parent})
+		if err != nil {
+			return nil, err
+		}
+
+		layers = append([]*models.ServiceConfig{layer}, layers...)
+		parent = layer.Spec.Parent
+	}
+
+	return layers, nil
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func getPayloadTooLargeError(size int, max int) string {
+	return fmt.Sprintf("config payload of %d bytes exceeds the limit of %d bytes", size, max)
+}
+
+func getTooManyKeysError(count int, max int) string {
+	return fmt.Sprintf("config has %d keys, the limit is %d", count, max)
+}
+
+func getKeyTooLongError(key string, max int) string {
+	return fmt.Sprintf("key '%.32s...' exceeds the key length limit of %d", key, max)
+}
+
+func getValueTooLongError(key string, max int) string {
+	return fmt.Sprintf("value of key '%s' exceeds the value length limit of %d", key, max)
+}
+
+func getTooManyVersionsError(serviceName string, max int) string {
+	return fmt.Sprintf("config of service '%s' has reached the limit of %d versions", serviceName, max)
+}
+
+func getStorageQuotaExceededError(namespace string, max int64) string {
+	return fmt.Sprintf("storage quota of %d bytes of namespace '%s' exceeded", max, namespace)
+}
+
+func getServiceQuotaExceededError(namespace string, max int) string {
+	return fmt.Sprintf("quota of %d services of namespace '%s' exceeded", max, namespace)
+}
+
+func (s *gRPCServer) GetUsage(ctx context.Context, req *pb.GetUsageRequest) (*pb.GetUsageResponse, error) {
+
+	srep := database.FromContext(ctx).Service()
+	usage, err := srep.Usage(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetUsageResponse{Resp: "Success"}
+	for _, u := range usage {
+		quota := s.quota(u.Namespace)
+		resp.Usage = append(resp.Usage, &pb.NamespaceUsage{
+			Namespace:   u.Namespace,
+			Services:    uint32(u.Services),
+			Versions:    uint32(u.Versions),
+			Bytes:       u.Bytes,
+			MaxBytes:    quota.MaxBytes,
+			MaxServices: uint32(quota.MaxServices),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *gRPCServer) checkPayload(confData string) error {
+	if max := s.limits.MaxPayloadBytes; max != 0 && len(confData) > max {
+		return status.Error(codes.ResourceExhausted, getPayloadTooLargeError(len(confData), max))
+	}
+
+	return nil
+}
+
+// checkLimits checks the keys and values of the service config against the
+// size limits.
+func (s *gRPCServer) checkLimits(sc *models.ServiceConfig) error {
+	if max := s.limits.MaxKeys; max != 0 && len(sc.Data) > max {
+		return status.Error(codes.ResourceExhausted, getTooManyKeysError(len(sc.Data), max))
+	}
+
+	for k, v := range sc.Data {
+		if max := s.limits.MaxKeyLength; max != 0 && len(k) > max {
+			return status.Error(codes.ResourceExhausted, getKeyTooLongError(k, max))
+		}
+		if max := s.limits.MaxValueLength; max != 0 && len(v) > max {
+			return status.Error(codes.ResourceExhausted, getValueTooLongError(k, max))
+		}
+	}
+
+	return nil
+}
+
+// checkQuota checks that storing the service config as a new version, or as
+// the first version of a new service, keeps the service under the version
+// limit and its namespace under its quota. It locks the namespace, the write
+// must follow in the transaction of ctx.
+func (s *gRPCServer) checkQuota(ctx context.Context, sc *models.ServiceConfig, created bool) error {
+	namespace := models.Namespace(sc.Service)
+	quota := s.quota(namespace)
+	if s.limits.MaxVersions == 0 && quota.MaxBytes == 0 && quota.MaxServices == 0 {
+		return nil
+	}
+
+	srep := database.FromContext(ctx).Service()
+	if err := srep.LockNamespace(namespace); err != nil {
+		return err
+	}
+
+	if max := s.limits.MaxVersions; max != 0 && !created {
+		count, err := srep.VersionCount(sc.Service)
+		if err != nil {
+			return err
+		} else if count >= max {
+			return status.Error(codes.ResourceExhausted, getTooManyVersionsError(sc.Service, max))
+		}
+	}
+
+	if quota.MaxBytes == 0 && quota.MaxServices == 0 {
+		return nil
+	}
+
+	usage := &models.Usage{Namespace: namespace}
+	if u, err := srep.Usage(namespace); err != nil {
+		return err
+	} else if len(u) != 0 {
+		usage = u[0]
+	}
+
+	if created && quota.MaxServices != 0 && usage.Services >= quota.MaxServices {
+		return status.Error(codes.ResourceExhausted, getServiceQuotaExceededError(namespace, quota.MaxServices))
+	}
+
+	if quota.MaxBytes != 0 {
+		configData, err := json.Marshal(sc.Data)
+		if err != nil {
+			return err
+		}
+		specData, err := json.Marshal(sc.Spec)
+		if err != nil {
+			return err
+		}
+
+		if usage.Bytes+models.StoredSize(configData, specData) > quota.MaxBytes {
+			return status.Error(codes.ResourceExhausted, getStorageQuotaExceededError(namespace, quota.MaxBytes))
+		}
+	}
+
+	return nil
+}
+
+// withQuota checks the quota of the service config and runs write in the same
+// transaction, so that concurrent writes can't exceed the quota together.
+func (s *gRPCServer) withQuota(ctx context.Context, sc *models.ServiceConfig, created bool, write func(psql *database.PostgreSQL) error) error {
+	return database.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.checkQuota(ctx, sc, created); err != nil {
+			return err
+		}
+		return write(database.FromContext(ctx))
+	})
+}
+
+func (s *gRPCServer) quota(namespace string) Quota {
+	if quota, found := s.quotas[namespace]; found {
+		return quota
+	}
+	return s.defaultQuota
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+)
+
+func (s *gRPCServer) SetKeyMetadata(ctx context.Context, req *pb.SetKeyMetadataRequest) (*pb.SetKeyMetadataResponse, error) {
+
+	metadata := &models.KeyMetadata{}
+	err := json.Unmarshal([]byte(req.MetadataData), &metadata)
+	if err != nil {
+		return nil, invalidArgument("metadataData", err.Error())
+	}
+
+	mrep := database.FromContext(ctx).KeyMetadata()
+	metadata, err = mrep.Create(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SetKeyMetadataResponse{Resp: "Success", Version: metadata.Version}, nil
+}
+
+func (s *gRPCServer) ReadKeyMetadata(ctx context.Context, req *pb.ReadKeyMetadataRequest) (*pb.ReadKeyMetadataResponse, error) {
+
+	mrep := database.FromContext(ctx).KeyMetadata()
+	metadata, err := mrep.Read(&models.KeyMetadata{
+		Service: req.ServiceName,
+		Version: req.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metadataData, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ReadKeyMetadataResponse{Resp: "Success", Version: metadata.Version, MetadataData: string(metadataData)}, nil
+}
+
+// deprecationWarnings returns the warnings for the deprecated keys the service
+// config sets.
+func deprecationWarnings(ctx context.Context, sc *models.ServiceConfig, prev *models.ServiceConfig) ([]string, error) {
+	metadata, err := database.FromContext(ctx).KeyMetadata().Latest(sc.Service)
+	if err != nil || metadata == nil {
+		return nil, err
+	}
+
+	return metadata.DeprecationWarnings(sc, prev), nil
+}
+
+// keyInfos returns the metadata of the keys present in data.
+func keyInfos(metadata *models.KeyMetadata, data map[string]string) map[string]*pb.KeyInfo {
+	infos := map[string]*pb.KeyInfo{}
+	for k, info := range metadata.Keys {
+		if _, found := data[k]; !found {
+			continue
+		}
+		infos[k] = &pb.KeyInfo{
+			Description:   info.Description,
+			Owner:         info.Owner,
+			AllowedValues: info.AllowedValues,
+			Deprecated:    info.Deprecated,
+			Link:          info.Link,
+		}
+	}
+
+	return infos
+}
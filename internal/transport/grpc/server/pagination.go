@@ -0,0 +1,49 @@
+package server
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+func getInvalidPageTokenError() string {
+	return fmt.Sprintf("invalid page token")
+}
+
+func pageSize(size uint32) int {
+	if size == 0 {
+		return defaultPageSize
+	} else if size > maxPageSize {
+		return maxPageSize
+	}
+	return int(size)
+}
+
+// encodePageToken returns the opaque token of the page starting at the
+// position, which is an offset or a version depending on the listing.
+func encodePageToken(position int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(position)))
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, invalidArgument("pageToken", getInvalidPageTokenError())
+	}
+
+	position, err := strconv.Atoi(string(b))
+	if err != nil || position < 0 {
+		return 0, invalidArgument("pageToken", getInvalidPageTokenError())
+	}
+
+	return position, nil
+}
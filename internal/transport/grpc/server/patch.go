@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+)
+
+func getUnknownPatchTypeError(patchType string) string {
+	return fmt.Sprintf("unknown patch type '%s'", patchType)
+}
+
+func (s *gRPCServer) Patch(ctx context.Context, req *pb.PatchRequest) (*pb.PatchResponse, error) {
+
+	if err := s.checkPayload(req.Patch); err != nil {
+		return nil, err
+	}
+
+	screp := database.FromContext(ctx).ServiceConfig()
+	prevServiceConfig, err := screp.Read(&models.ServiceConfig{Service: req.ServiceName})
+	if err != nil {
+		return nil, err
+	}
+
+	// the patch applies to masked secrets so that the secrets it leaves
+	// untouched keep their sealed value
+	data := maskedData(prevServiceConfig)
+
+	var patched map[string]string
+	switch req.PatchType {
+	case "", models.PatchMerge:
+		patched, err = models.MergePatch(data, []byte(req.Patch))
+	case models.PatchJSON:
+		patched, err = models.ApplyJSONPatch(data, []byte(req.Patch))
+	default:
+		return nil, invalidArgument("patchType", getUnknownPatchTypeError(req.PatchType))
+	}
+	if err != nil {
+		return nil, invalidArgument("patch", err.Error())
+	}
+
+	changes := models.DiffData(data, patched)
+	maskChanges(changes, prevServiceConfig.Spec.Secrets)
+
+	serviceConfig, warnings, err := s.writeData(ctx, prevServiceConfig, patched,
+		baseVersion(prevServiceConfig, req.ExpectedVersion), req.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.PatchResponse{
+		Resp:     "Success",
+		Version:  serviceConfig.Version,
+		Changes:  toKeyChanges(changes),
+		Warnings: warnings,
+	}, nil
+}
+
+// writeData stores data as the version of prev following baseVersion, it goes
+// through the checks of Update and returns the deprecation warnings.
+func (s *gRPCServer) writeData(ctx context.Context, prev *models.ServiceConfig, data map[string]string, baseVersion uint32, message string) (*models.ServiceConfig, []string, error) {
+	serviceConfig := prev.WithData(data)
+	serviceConfig.Message = message
+
+	if err := s.checkLimits(serviceConfig); err != nil {
+		return nil, nil, err
+	}
+
+	models.SetExpiryPriors(serviceConfig, prev)
+
+	if err := s.sealSecrets(ctx, serviceConfig, prev); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.validateServiceConfig(ctx, serviceConfig); err != nil {
+		return nil, nil, err
+	}
+
+	warnings, err := deprecationWarnings(ctx, serviceConfig, prev)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.withQuota(ctx, serviceConfig, false, func(psql *database.PostgreSQL) error {
+		_, err := psql.ServiceConfig().UpdateFrom(serviceConfig, baseVersion)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return serviceConfig, warnings, nil
+}
+
+// baseVersion returns the version a write expects to follow: the expected
+// version of the request if set, or the version prev that it was based on.
+func baseVersion(prev *models.ServiceConfig, expectedVersion uint32) uint32 {
+	if expectedVersion != 0 {
+		return expectedVersion
+	}
+	return prev.Version
+}
+
+// maskedData returns a copy of the stored data of the service config with
+// its secrets masked.
+func maskedData(sc *models.ServiceConfig) map[string]string {
+	data := make(map[string]string, len(sc.Data))
+	for k, v := range sc.Data {
+		data[k] = v
+	}
+	for _, k := range sc.Spec.Secrets {
+		if _, found := data[k]; found {
+			data[k] = models.SecretMask
+		}
+	}
+	return data
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"sort"
+	"time"
+)
+
+type secretsMode int
+
+const (
+	secretsMasked secretsMode = iota
+	secretsRevealed
+	secretsSealed
+)
+
+type renderOptions struct {
+	layerOnly bool
+	raw       bool
+	context   map[string]string
+	secrets   secretsMode
+}
+
+type renderedConfig struct {
+	data       map[string]string
+	provenance map[string]string
+	secrets    []string
+}
+
+// render returns the data of the service config as it is served to clients:
+// without the expired keys, merged with its parents and with the rules
+// matching the read context applied unless only the layer is requested, with
+// secrets masked, revealed or left sealed and with references resolved unless
+// raw data is requested.
+func (s *gRPCServer) render(ctx context.Context, sc *models.ServiceConfig, opts renderOptions) (*renderedConfig, error) {
+	layers := []*models.ServiceConfig{sc}
+	if !opts.layerOnly {
+		var err error
+		if layers, err = resolveLayers(ctx, sc); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	for i, layer := range layers {
+		layers[i] = layer.WithoutExpired(now)
+	}
+
+	data, provenance, err := models.MergeLayers(layers)
+	if err != nil {
+		return nil, invalidArgument("merge", err.Error())
+	}
+
+	if !opts.layerOnly {
+		if data, err = models.ResolveRules(data, models.LayerRules(layers), opts.context); err != nil {
+			return nil, invalidArgument("rules", err.Error())
+		}
+	}
+
+	var secretKeys []string
+	for k := range models.SecretKeys(layers) {
+		v, found := data[k]
+		if !found {
+			continue
+		}
+		secretKeys = append(secretKeys, k)
+
+		switch opts.secrets {
+		case secretsMasked:
+			data[k] = models.SecretMask
+		case secretsRevealed:
+			if data[k], err = s.revealSecret(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	sort.Strings(secretKeys)
+
+	if !opts.raw && !opts.layerOnly {
+		data, err = models.Interpolate(sc.Service, data, func(serviceName string) (map[string]string, error) {
+			return s.loadServiceData(ctx, serviceName, opts)
+		})
+		if err != nil {
+			return nil, asInvalidArgument("confData", err)
+		}
+	}
+
+	return &renderedConfig{
+		data:       data,
+		provenance: provenance,
+		secrets:    secretKeys,
+	}, nil
+}
+
+func (s *gRPCServer) loadServiceData(ctx context.Context, serviceName string, opts renderOptions) (map[string]string, error) {
+	sc, err := database.FromContext(ctx).ServiceConfig().Read(&models.ServiceConfig{Service: serviceName})
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := s.render(ctx, sc, renderOptions{raw: true, context: opts.context, secrets: opts.secrets})
+	if err != nil {
+		return nil, err
+	}
+
+	return rc.data, nil
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	"strings"
+)
+
+func getIncompatibleSchemaError(issues []string) string {
+	return fmt.Sprintf("schema is incompatible: %s", strings.Join(issues, "; "))
+}
+
+func getConfigDoesNotMatchSchemaError(issues []string) string {
+	return fmt.Sprintf("config does not match schema: %s", strings.Join(issues, "; "))
+}
+
+func (s *gRPCServer) RegisterSchema(ctx context.Context, req *pb.RegisterSchemaRequest) (*pb.RegisterSchemaResponse, error) {
+
+	schema := &models.Schema{}
+	err := json.Unmarshal([]byte(req.SchemaData), &schema)
+	if err != nil {
+		return nil, invalidArgument("schemaData", err.Error())
+	}
+
+	// the schema is checked against the latest one while no other version can
+	// be registered
+	var issues []string
+	err = database.WithTransaction(ctx, func(ctx context.Context) error {
+		srep := database.FromContext(ctx).Schema()
+		if err := srep.Lock(schema.Service); err != nil {
+			return err
+		}
+
+		prevSchema, err := srep.Latest(schema.Service)
+		if err != nil {
+			return err
+		}
+
+		if prevSchema != nil {
+			issues = append(issues, schema.CheckCompatibility(prevSchema)...)
+		}
+
+		screp := database.FromContext(ctx).ServiceConfig()
+		serviceConfigs, err := screp.ReadAllVersions(&models.ServiceConfig{Service: schema.Service})
+		if err != nil {
+			return err
+		}
+		for _, sc := range serviceConfigs {
+			rc, err := s.render(ctx, sc, renderOptions{secrets: secretsRevealed})
+			if err != nil {
+				return err
+			}
+			for _, issue := range schema.Validate(rc.data) {
+				issues = append(issues, fmt.Sprintf("config version %d: %s", sc.Version, issue))
+			}
+		}
+
+		if len(issues) != 0 && !req.Force {
+			return failedPrecondition(preconditionIncompatibleSchema, schema.Service, 0, getIncompatibleSchemaError(issues))
+		}
+
+		schema, err = srep.Create(schema)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RegisterSchemaResponse{Resp: "Success", Version: schema.Version, Issues: issues}, nil
+}
+
+func (s *gRPCServer) ReadSchema(ctx context.Context, req *pb.ReadSchemaRequest) (*pb.ReadSchemaResponse, error) {
+
+	srep := database.FromContext(ctx).Schema()
+	schema, err := srep.Read(&models.Schema{
+		Service: req.ServiceName,
+		Version: req.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	schemaData, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ReadSchemaResponse{Resp: "Success", Version: schema.Version, SchemaData: string(schemaData)}, nil
+}
+
+// validateServiceConfig checks that the service config can be rendered and
+// that the rendered config matches the service schema.
+func (s *gRPCServer) validateServiceConfig(ctx context.Context, sc *models.ServiceConfig) error {
+
+	rc, err := s.render(ctx, sc, renderOptions{secrets: secretsRevealed})
+	if err != nil {
+		return err
+	}
+
+	schema, err := database.FromContext(ctx).Schema().Latest(sc.Service)
+	if err != nil {
+		return err
+	} else if schema == nil {
+		return nil
+	}
+
+	if issues := schema.Validate(rc.data); len(issues) != 0 {
+		return invalidArgument("confData", getConfigDoesNotMatchSchemaError(issues))
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/secrets"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+const authorizationHeader = "authorization"
+
+func getSecretsKeyNotConfiguredError() string {
+	return fmt.Sprintf("secrets encryption key is not configured")
+}
+
+func (s *gRPCServer) ReadSecrets(ctx context.Context, req *pb.ReadSecretsRequest) (*pb.ReadSecretsResponse, error) {
+
+	if !s.canReadSecrets(ctx) {
+		return nil, status.Error(codes.PermissionDenied, "secrets permission is required")
+	}
+
+	version, err := resolveTag(ctx, req.ServiceName, req.Tag, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	screp := database.FromContext(ctx).ServiceConfig()
+	serviceConfig, err := screp.Read(&models.ServiceConfig{
+		Service: req.ServiceName,
+		Version: version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := s.render(ctx, serviceConfig, renderOptions{
+		layerOnly: req.LayerOnly,
+		raw:       req.Raw,
+		context:   req.Context,
+		secrets:   secretsRevealed,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	secretsData := make(map[string]string, len(rc.secrets))
+	for _, k := range rc.secrets {
+		secretsData[k] = rc.data[k]
+	}
+
+	return &pb.ReadSecretsResponse{Resp: "Success", Secrets: secretsData}, nil
+}
+
+func (s *gRPCServer) canReadSecrets(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+
+	for _, v := range md.Get(authorizationHeader) {
+		if token := strings.TrimPrefix(v, "Bearer "); s.secretsTokens[token] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sealSecrets encrypts the secret values of the service config before it is
+// stored. Keys marked as secrets in prev or in a parent layer become secrets
+// of the config as well and masked values sent back by clients keep the value
+// stored in prev.
+func (s *gRPCServer) sealSecrets(ctx context.Context, sc *models.ServiceConfig, prev *models.ServiceConfig) error {
+	layers, err := resolveLayers(ctx, sc)
+	if err != nil {
+		return err
+	}
+	if prev != nil {
+		layers = append(layers, prev)
+	}
+
+	for k := range models.SecretKeys(layers) {
+		if _, found := sc.Data[k]; found || hasRule(sc.Spec.Rules, k) {
+			sc.Spec.MarkSecrets(k)
+		}
+	}
+	sc.Spec.MarkSecrets()
+
+	for _, k := range sc.Spec.Secrets {
+		v, found := sc.Data[k]
+		if !found {
+			continue
+		}
+
+		if v == models.SecretMask && prev != nil && secrets.IsEncrypted(prev.Data[k]) {
+			sc.Data[k] = prev.Data[k]
+			continue
+		}
+
+		if s.cipher == nil {
+			return failedPrecondition(preconditionSecretsKey, "secrets", 0, getSecretsKeyNotConfiguredError())
+		}
+		sc.Data[k] = s.cipher.Encrypt(v)
+	}
+
+	secretKeys := map[string]bool{}
+	for _, k := range sc.Spec.Secrets {
+		secretKeys[k] = true
+	}
+	for i, r := range sc.Spec.Rules {
+		if !secretKeys[r.Key] || secrets.IsEncrypted(r.Value) {
+			continue
+		}
+
+		if s.cipher == nil {
+			return failedPrecondition(preconditionSecretsKey, "secrets", 0, getSecretsKeyNotConfiguredError())
+		}
+		sc.Spec.Rules[i].Value = s.cipher.Encrypt(r.Value)
+	}
+
+	return nil
+}
+
+func hasRule(rules []models.Rule, key string) bool {
+	for _, r := range rules {
+		if r.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *gRPCServer) sealSecret(v string) (string, error) {
+	if secrets.IsEncrypted(v) {
+		return v, nil
+	}
+
+	if s.cipher == nil {
+		return "", failedPrecondition(preconditionSecretsKey, "secrets", 0, getSecretsKeyNotConfiguredError())
+	}
+
+	return s.cipher.Encrypt(v), nil
+}
+
+func (s *gRPCServer) revealSecret(v string) (string, error) {
+	if !secrets.IsEncrypted(v) {
+		return v, nil
+	}
+
+	if s.cipher == nil {
+		return "", failedPrecondition(preconditionSecretsKey, "secrets", 0, getSecretsKeyNotConfiguredError())
+	}
+
+	return s.cipher.Decrypt(v)
+}
@@ -2,35 +2,124 @@ package server
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/formats"
 	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/secrets"
 	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	pbv2 "github.com/wphylici/contest-cloud/internal/transport/grpc/pb/v2"
+	"github.com/wphylici/contest-cloud/internal/watch"
 	"google.golang.org/grpc"
+	"time"
 )
 
 type gRPCServer struct {
 	pb.UnimplementedConfigControllerServer
+	cipher               *secrets.Cipher
+	secretsTokens        map[string]bool
+	limits               Limits
+	defaultQuota         Quota
+	quotas               map[string]Quota
+	hub                  *watch.Hub
+	watchHeartbeat       time.Duration
+	idempotencyRetention time.Duration
+	idempotencyLease     time.Duration
 }
 
-func NewGRPCServer() *grpc.Server {
-	srv := gRPCServer{}
+func getNonPositiveWatchHeartbeatError(watchHeartbeat string) string {
+	return fmt.Sprintf("watch heartbeat '%s' must be positive", watchHeartbeat)
+}
+
+func getNonPositiveWatchBufferSizeError(watchBufferSize int) string {
+	return fmt.Sprintf("watch buffer size %d must be positive", watchBufferSize)
+}
+
+func getNonPositiveIdempotencyLeaseError(idempotencyLease string) string {
+	return fmt.Sprintf("idempotency lease '%s' must be positive", idempotencyLease)
+}
+
+func NewGRPCServer(config *Config) (*grpc.Server, error) {
+	srv := gRPCServer{
+		secretsTokens: map[string]bool{},
+		limits:        config.Limits,
+		defaultQuota:  config.DefaultQuota,
+		quotas:        config.Quotas,
+	}
+
+	if config.SecretsKey != "" {
+		cipher, err := secrets.NewCipher(config.SecretsKey)
+		if err != nil {
+			return nil, err
+		}
+		srv.cipher = cipher
+	}
+	for _, token := range config.SecretsTokens {
+		srv.secretsTokens[token] = true
+	}
+
+	watchHeartbeat, err := time.ParseDuration(config.WatchHeartbeat)
+	if err != nil {
+		return nil, err
+	}
+	if watchHeartbeat <= 0 {
+		return nil, fmt.Errorf(getNonPositiveWatchHeartbeatError(config.WatchHeartbeat))
+	}
+	srv.watchHeartbeat = watchHeartbeat
+
+	idempotencyRetention, err := time.ParseDuration(config.IdempotencyRetention)
+	if err != nil {
+		return nil, err
+	}
+	srv.idempotencyRetention = idempotencyRetention
+
+	idempotencyLease, err := time.ParseDuration(config.IdempotencyLease)
+	if err != nil {
+		return nil, err
+	}
+	if idempotencyLease <= 0 {
+		return nil, fmt.Errorf(getNonPositiveIdempotencyLeaseError(config.IdempotencyLease))
+	}
+	srv.idempotencyLease = idempotencyLease
 
-	s := grpc.NewServer()
+	if config.WatchBufferSize <= 0 {
+		return nil, fmt.Errorf(getNonPositiveWatchBufferSizeError(config.WatchBufferSize))
+	}
+	changes := make(chan *models.ConfigChange, config.WatchBufferSize)
+	if err = database.Psql.ListenChanges(changes); err != nil {
+		return nil, err
+	}
+	srv.hub = watch.NewHub(config.WatchBufferSize)
+	go srv.hub.Run(changes)
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryErrorInterceptor, srv.unaryIdempotencyInterceptor),
+		grpc.ChainStreamInterceptor(streamErrorInterceptor),
+	)
 	pb.RegisterConfigControllerServer(s, &srv)
-	return s
+	pbv2.RegisterConfigControllerServer(s, &v2Server{s: &srv})
+	return s, nil
 }
 
 func (s *gRPCServer) Create(ctx context.Context, req *pb.CreateRequest) (*pb.CreateResponse, error) {
 
-	serviceConfig := &models.ServiceConfig{}
-	err := json.Unmarshal([]byte(req.ConfData), &serviceConfig)
+	serviceConfig, err := s.decodeServiceConfig(req.ConfData, req.Format, req.ServiceName)
 	if err != nil {
 		return nil, err
 	}
 
-	screp := database.Psql.ServiceConfig()
-	serviceConfig, err = screp.Create(serviceConfig)
+	if err = s.sealSecrets(ctx, serviceConfig, nil); err != nil {
+		return nil, err
+	}
+
+	if err = s.validateServiceConfig(ctx, serviceConfig); err != nil {
+		return nil, err
+	}
+
+	err = s.withQuota(ctx, serviceConfig, true, func(psql *database.PostgreSQL) error {
+		_, err := psql.ServiceConfig().Create(serviceConfig)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -40,41 +129,96 @@ func (s *gRPCServer) Create(ctx context.Context, req *pb.CreateRequest) (*pb.Cre
 
 func (s *gRPCServer) Read(ctx context.Context, req *pb.ReadRequest) (*pb.ReadResponse, error) {
 
-	screp := database.Psql.ServiceConfig()
+	version, err := resolveTag(ctx, req.ServiceName, req.Tag, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	screp := database.FromContext(ctx).ServiceConfig()
 	serviceConfig, err := screp.Read(&models.ServiceConfig{
 		Service: req.ServiceName,
-		Version: req.Version,
+		Version: version,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	configData, err := json.Marshal(serviceConfig.Data)
+	rc, err := s.render(ctx, serviceConfig, renderOptions{
+		layerOnly: req.LayerOnly,
+		raw:       req.Raw,
+		context:   req.Context,
+		secrets:   secretsMasked,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &pb.ReadResponse{Resp: "Success", ConfData: string(configData)}, nil
+	configData, err := formats.Encode(req.OutputFormat, rc.data)
+	if err != nil {
+		return nil, invalidArgument("outputFormat", err.Error())
+	}
+
+	resp := &pb.ReadResponse{
+		Resp:       "Success",
+		ConfData:   string(configData),
+		Parent:     serviceConfig.Spec.Parent,
+		Provenance: rc.provenance,
+		Secrets:    rc.secrets,
+	}
+
+	if req.WithMetadata {
+		metadata, err := database.FromContext(ctx).KeyMetadata().Latest(req.ServiceName)
+		if err != nil {
+			return nil, err
+		} else if metadata != nil {
+			resp.Metadata = keyInfos(metadata, rc.data)
+			resp.MetadataVersion = metadata.Version
+		}
+	}
+
+	return resp, nil
 }
 
 func (s *gRPCServer) Update(ctx context.Context, req *pb.UpdateRequest) (*pb.UpdateResponse, error) {
-	serviceConfig := &models.ServiceConfig{}
-	err := json.Unmarshal([]byte(req.ConfData), &serviceConfig)
+	serviceConfig, err := s.decodeServiceConfig(req.ConfData, req.Format, req.ServiceName)
 	if err != nil {
 		return nil, err
 	}
 
-	screp := database.Psql.ServiceConfig()
-	serviceConfig, err = screp.Update(serviceConfig)
+	screp := database.FromContext(ctx).ServiceConfig()
+	prevServiceConfig, err := screp.Read(&models.ServiceConfig{Service: serviceConfig.Service})
+	if err != nil {
+		return nil, err
+	}
+
+	models.SetExpiryPriors(serviceConfig, prevServiceConfig)
+
+	if err = s.sealSecrets(ctx, serviceConfig, prevServiceConfig); err != nil {
+		return nil, err
+	}
+
+	if err = s.validateServiceConfig(ctx, serviceConfig); err != nil {
+		return nil, err
+	}
+
+	warnings, err := deprecationWarnings(ctx, serviceConfig, prevServiceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.withQuota(ctx, serviceConfig, false, func(psql *database.PostgreSQL) error {
+		_, err := psql.ServiceConfig().UpdateFrom(serviceConfig, prevServiceConfig.Version)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &pb.UpdateResponse{Resp: "Success"}, nil
+	return &pb.UpdateResponse{Resp: "Success", Warnings: warnings}, nil
 }
 
 func (s *gRPCServer) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
-	screp := database.Psql.ServiceConfig()
+	screp := database.FromContext(ctx).ServiceConfig()
 	_, err := screp.Delete(&models.ServiceConfig{
 		Service: req.ServiceName,
 		Version: req.Version,
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	"sort"
+	"time"
+)
+
+func getUnknownSortFieldError(sortBy string) string {
+	return fmt.Sprintf("unknown sort field '%s'", sortBy)
+}
+
+func (s *gRPCServer) SetLabels(ctx context.Context, req *pb.SetLabelsRequest) (*pb.SetLabelsResponse, error) {
+
+	if err := models.ValidateLabels(req.Labels); err != nil {
+		return nil, invalidArgument("labels", err.Error())
+	}
+
+	srep := database.FromContext(ctx).Service()
+	service, err := srep.SetLabels(&models.Service{
+		Name:        req.ServiceName,
+		Labels:      req.Labels,
+		Annotations: req.Annotations,
+	}, req.RemoveLabels, req.RemoveAnnotations)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SetLabelsResponse{Resp: "Success", Service: toServiceInfo(service)}, nil
+}
+
+func (s *gRPCServer) ListServices(ctx context.Context, req *pb.ListServicesRequest) (*pb.ListServicesResponse, error) {
+
+	selector, err := models.ParseSelector(req.Selector)
+	if err != nil {
+		return nil, invalidArgument("selector", err.Error())
+	}
+
+	less, err := serviceOrder(req.SortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	srep := database.FromContext(ctx).Service()
+	services, err := srep.List(req.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*models.Service
+	for _, service := range services {
+		if selector.Matches(service.Labels) {
+			matched = append(matched, service)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if req.Descending {
+			return less(matched[j], matched[i])
+		}
+		return less(matched[i], matched[j])
+	})
+
+	resp := &pb.ListServicesResponse{Resp: "Success"}
+	if offset >= len(matched) {
+		return resp, nil
+	}
+
+	end := offset + pageSize(req.PageSize)
+	if end < len(matched) {
+		resp.NextPageToken = encodePageToken(end)
+	} else {
+		end = len(matched)
+	}
+
+	for _, service := range matched[offset:end] {
+		resp.Services = append(resp.Services, toServiceInfo(service))
+	}
+
+	return resp, nil
+}
+
+func serviceOrder(sortBy string) (func(a, b *models.Service) bool, error) {
+	switch sortBy {
+	case "", "name":
+		return func(a, b *models.Service) bool { return a.Name < b.Name }, nil
+	case "latestVersion":
+		return func(a, b *models.Service) bool { return a.LatestVersion < b.LatestVersion }, nil
+	case "updatedAt":
+		return func(a, b *models.Service) bool { return a.UpdatedAt.Before(b.UpdatedAt) }, nil
+	default:
+		return nil, invalidArgument("sortBy", getUnknownSortFieldError(sortBy))
+	}
+}
+
+func toServiceInfo(service *models.Service) *pb.ServiceInfo {
+	return &pb.ServiceInfo{
+		ServiceName:   service.Name,
+		Labels:        service.Labels,
+		Annotations:   service.Annotations,
+		LatestVersion: service.LatestVersion,
+		UpdatedAt:     formatTime(service.UpdatedAt),
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
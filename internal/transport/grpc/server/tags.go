@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	"time"
+)
+
+func getTagVersionMismatchError(tagName string, tagVersion uint32, version uint32) string {
+	return fmt.Sprintf("tag '%s' points to version '%d', not '%d'", tagName, tagVersion, version)
+}
+
+func (s *gRPCServer) CreateTag(ctx context.Context, req *pb.CreateTagRequest) (*pb.CreateTagResponse, error) {
+
+	if err := models.ValidateTagName(req.Name); err != nil {
+		return nil, invalidArgument("name", err.Error())
+	}
+
+	trep := database.FromContext(ctx).Tag()
+	tag, err := trep.Create(&models.Tag{
+		Service: req.ServiceName,
+		Name:    req.Name,
+		Version: req.Version,
+		Movable: req.Movable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateTagResponse{Resp: "Success", Tag: toTag(tag)}, nil
+}
+
+func (s *gRPCServer) MoveTag(ctx context.Context, req *pb.MoveTagRequest) (*pb.MoveTagResponse, error) {
+
+	trep := database.FromContext(ctx).Tag()
+	tag, err := trep.Move(&models.Tag{
+		Service: req.ServiceName,
+		Name:    req.Name,
+		Version: req.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.MoveTagResponse{Resp: "Success", Tag: toTag(tag)}, nil
+}
+
+func (s *gRPCServer) ListTags(ctx context.Context, req *pb.ListTagsRequest) (*pb.ListTagsResponse, error) {
+
+	trep := database.FromContext(ctx).Tag()
+	tags, err := trep.List(req.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := trep.History(req.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListTagsResponse{Resp: "Success"}
+	for _, tag := range tags {
+		resp.Tags = append(resp.Tags, toTag(tag))
+	}
+	for _, move := range history {
+		resp.History = append(resp.History, &pb.TagMove{
+			Name:       move.Name,
+			OldVersion: move.OldVersion,
+			NewVersion: move.NewVersion,
+			MovedAt:    move.MovedAt.Format(time.RFC3339),
+		})
+	}
+
+	return resp, nil
+}
+
+// resolveTag returns the version the tag points to or version itself if no
+// tag is given.
+func resolveTag(ctx context.Context, serviceName string, tagName string, version uint32) (uint32, error) {
+	if tagName == "" {
+		return version, nil
+	}
+
+	tag, err := database.FromContext(ctx).Tag().Read(&models.Tag{
+		Service: serviceName,
+		Name:    tagName,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if version != 0 && version != tag.Version {
+		return 0, invalidArgument("tag", getTagVersionMismatchError(tagName, tag.Version, version))
+	}
+
+	return tag.Version, nil
+}
+
+func toTag(tag *models.Tag) *pb.Tag {
+	return &pb.Tag{
+		Name:    tag.Name,
+		Version: tag.Version,
+		Movable: tag.Movable,
+	}
+}
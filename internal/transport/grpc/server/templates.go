@@ -0,0 +1,216 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/secrets"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+)
+
+func (s *gRPCServer) CreateTemplate(ctx context.Context, req *pb.CreateTemplateRequest) (*pb.CreateTemplateResponse, error) {
+
+	template := &models.Template{}
+	err := json.Unmarshal([]byte(req.TemplateData), &template)
+	if err != nil {
+		return nil, invalidArgument("templateData", err.Error())
+	}
+
+	if err = transformTemplateSecrets(template, s.sealSecret); err != nil {
+		return nil, err
+	}
+
+	trep := database.FromContext(ctx).Template()
+	template, err = trep.Create(template)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateTemplateResponse{Resp: "Success", Version: template.Version}, nil
+}
+
+func (s *gRPCServer) ReadTemplate(ctx context.Context, req *pb.ReadTemplateRequest) (*pb.ReadTemplateResponse, error) {
+
+	trep := database.FromContext(ctx).Template()
+	template, err := trep.Read(&models.Template{
+		Name:    req.TemplateName,
+		Version: req.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transformTemplateSecrets(template, func(v string) (string, error) {
+		return models.SecretMask, nil
+	})
+
+	templateData, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ReadTemplateResponse{Resp: "Success", Version: template.Version, TemplateData: string(templateData)}, nil
+}
+
+func (s *gRPCServer) CreateFromTemplate(ctx context.Context, req *pb.CreateFromTemplateRequest) (*pb.CreateFromTemplateResponse, error) {
+
+	trep := database.FromContext(ctx).Template()
+	template, err := trep.Read(&models.Template{
+		Name:    req.TemplateName,
+		Version: req.TemplateVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = transformTemplateSecrets(template, s.revealSecret); err != nil {
+		return nil, err
+	}
+
+	serviceConfig, err := template.Instantiate(req.ServiceName, req.Parameters)
+	if err != nil {
+		return nil, invalidArgument("parameters", err.Error())
+	}
+
+	if err = s.checkLimits(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if err = s.sealSecrets(ctx, serviceConfig, nil); err != nil {
+		return nil, err
+	}
+
+	if err = s.validateServiceConfig(ctx, serviceConfig); err != nil {
+		return nil, err
+	}
+
+	// parameters that end up in secret values are stored sealed
+	parameters := make(map[string]string, len(req.Parameters))
+	secretParameters := template.SecretParameters()
+	for name, v := range req.Parameters {
+		if secretParameters[name] {
+			if v, err = s.sealSecret(v); err != nil {
+				return nil, err
+			}
+		}
+		parameters[name] = v
+	}
+
+	err = s.withQuota(ctx, serviceConfig, true, func(psql *database.PostgreSQL) error {
+		_, err := psql.Template().CreateInstance(serviceConfig, &models.TemplateInstance{
+			Service:         serviceConfig.Service,
+			Template:        template.Name,
+			TemplateVersion: template.Version,
+			Parameters:      parameters,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateFromTemplateResponse{Resp: "Success", TemplateVersion: template.Version}, nil
+}
+
+func (s *gRPCServer) TemplateReport(ctx context.Context, req *pb.TemplateReportRequest) (*pb.TemplateReportResponse, error) {
+
+	trep := database.FromContext(ctx).Template()
+	latest, err := trep.Read(&models.Template{Name: req.TemplateName})
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := trep.Instances(req.TemplateName)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := map[uint32]*models.Template{latest.Version: latest}
+	screp := database.FromContext(ctx).ServiceConfig()
+
+	resp := &pb.TemplateReportResponse{Resp: "Success", LatestVersion: latest.Version}
+	for _, instance := range instances {
+		template, found := templates[instance.TemplateVersion]
+		if !found {
+			template, err = trep.Read(&models.Template{
+				Name:    instance.Template,
+				Version: instance.TemplateVersion,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err = transformTemplateSecrets(template, s.revealSecret); err != nil {
+				return nil, err
+			}
+			templates[instance.TemplateVersion] = template
+		}
+
+		for name, v := range instance.Parameters {
+			if instance.Parameters[name], err = s.revealSecret(v); err != nil {
+				return nil, err
+			}
+		}
+
+		expected, err := template.Instantiate(instance.Service, instance.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		if err = s.sealSecrets(ctx, expected, nil); err != nil {
+			return nil, err
+		}
+
+		current, err := screp.Read(&models.ServiceConfig{Service: instance.Service})
+		if err != nil {
+			return nil, err
+		}
+
+		driftedKeys := models.ChangedKeys(expected.Data, current.Data)
+		resp.Instances = append(resp.Instances, &pb.TemplateInstance{
+			ServiceName:     instance.Service,
+			TemplateVersion: instance.TemplateVersion,
+			Outdated:        instance.TemplateVersion < latest.Version,
+			Drifted:         len(driftedKeys) != 0 || expected.Spec.Parent != current.Spec.Parent,
+			DriftedKeys:     driftedKeys,
+		})
+	}
+
+	return resp, nil
+}
+
+// transformTemplateSecrets replaces the values of the secret keys of the
+// template, and the defaults of the parameters used in them, by the result of
+// transform. Placeholders can't be found in sealed values, so sealed defaults
+// are transformed as well.
+func transformTemplateSecrets(t *models.Template, transform func(v string) (string, error)) error {
+	secretKeys := map[string]bool{}
+	for _, k := range t.Spec.Secrets {
+		secretKeys[k] = true
+	}
+	secretParameters := t.SecretParameters()
+
+	var err error
+	for i, p := range t.Parameters {
+		if p.Default != "" && (secretParameters[p.Name] || secrets.IsEncrypted(p.Default)) {
+			if t.Parameters[i].Default, err = transform(p.Default); err != nil {
+				return err
+			}
+		}
+	}
+	for k, v := range t.Data {
+		if secretKeys[k] {
+			if t.Data[k], err = transform(v); err != nil {
+				return err
+			}
+		}
+	}
+	for i, r := range t.Spec.Rules {
+		if secretKeys[r.Key] {
+			if t.Spec.Rules[i].Value, err = transform(r.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
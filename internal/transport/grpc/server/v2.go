@@ -0,0 +1,343 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/formats"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	pbv2 "github.com/wphylici/contest-cloud/internal/transport/grpc/pb/v2"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"sort"
+	"time"
+)
+
+func getConfigRequiredError() string {
+	return fmt.Sprintf("config is required")
+}
+
+// v2Server serves the typed v2 API on top of the v1 server.
+type v2Server struct {
+	pbv2.UnimplementedConfigControllerServer
+	s *gRPCServer
+}
+
+func (v *v2Server) CreateConfig(ctx context.Context, req *pbv2.CreateConfigRequest) (*pbv2.ServiceConfig, error) {
+
+	serviceConfig, err := v.s.fromV2Config(req.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = v.s.sealSecrets(ctx, serviceConfig, nil); err != nil {
+		return nil, err
+	}
+
+	if err = v.s.validateServiceConfig(ctx, serviceConfig); err != nil {
+		return nil, err
+	}
+
+	err = v.s.withQuota(ctx, serviceConfig, true, func(psql *database.PostgreSQL) error {
+		_, err := psql.ServiceConfig().Create(serviceConfig)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	serviceConfig.Version = 1
+
+	return v.s.toV2Config(ctx, serviceConfig, renderOptions{layerOnly: true, secrets: secretsMasked})
+}
+
+func (v *v2Server) GetConfig(ctx context.Context, req *pbv2.GetConfigRequest) (*pbv2.ServiceConfig, error) {
+
+	serviceConfig, err := readVersion(ctx, req.ServiceName, req.Tag, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.s.toV2Config(ctx, serviceConfig, renderOptions{
+		layerOnly: req.LayerOnly,
+		raw:       req.Raw,
+		context:   req.Context,
+		secrets:   secretsMasked,
+	})
+}
+
+func (v *v2Server) UpdateConfig(ctx context.Context, req *pbv2.UpdateConfigRequest) (*pbv2.ServiceConfig, error) {
+
+	serviceConfig, err := v.s.fromV2Config(req.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	screp := database.FromContext(ctx).ServiceConfig()
+	prevServiceConfig, err := screp.Read(&models.ServiceConfig{Service: serviceConfig.Service})
+	if err != nil {
+		return nil, err
+	}
+
+	models.SetExpiryPriors(serviceConfig, prevServiceConfig)
+
+	if err = v.s.sealSecrets(ctx, serviceConfig, prevServiceConfig); err != nil {
+		return nil, err
+	}
+
+	if err = v.s.validateServiceConfig(ctx, serviceConfig); err != nil {
+		return nil, err
+	}
+
+	warnings, err := deprecationWarnings(ctx, serviceConfig, prevServiceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceConfig.Message = req.Message
+	err = v.s.withQuota(ctx, serviceConfig, false, func(psql *database.PostgreSQL) error {
+		_, err := psql.ServiceConfig().UpdateFrom(serviceConfig, baseVersion(prevServiceConfig, req.ExpectedVersion))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := v.s.toV2Config(ctx, serviceConfig, renderOptions{layerOnly: true, secrets: secretsMasked})
+	if err != nil {
+		return nil, err
+	}
+	config.Warnings = warnings
+
+	return config, nil
+}
+
+func (v *v2Server) DeleteConfig(ctx context.Context, req *pbv2.DeleteConfigRequest) (*emptypb.Empty, error) {
+
+	if _, err := v.s.Delete(ctx, &pb.DeleteRequest{ServiceName: req.ServiceName, Version: req.Version}); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (v *v2Server) Rollback(ctx context.Context, req *pbv2.RollbackRequest) (*pbv2.ServiceConfig, error) {
+
+	resp, err := v.s.Rollback(ctx, &pb.RollbackRequest{
+		ServiceName: req.ServiceName,
+		ToVersion:   req.ToVersion,
+		Message:     req.Message,
+		Tag:         req.Tag,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	serviceConfig, err := readVersion(ctx, req.ServiceName, "", resp.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.s.toV2Config(ctx, serviceConfig, renderOptions{layerOnly: true, secrets: secretsMasked})
+}
+
+func (v *v2Server) ListVersions(ctx context.Context, req *pbv2.ListVersionsRequest) (*pbv2.ListVersionsResponse, error) {
+
+	beforeVersion, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := pageSize(req.PageSize)
+
+	screp := database.FromContext(ctx).ServiceConfig()
+	versions, err := screp.ListVersions(&models.ServiceConfig{Service: req.ServiceName}, uint32(beforeVersion), limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pbv2.ListVersionsResponse{}
+	if len(versions) > limit {
+		versions = versions[:limit]
+		resp.NextPageToken = encodePageToken(int(versions[limit-1].Version))
+	}
+
+	for _, version := range versions {
+		resp.Versions = append(resp.Versions, toVersionMetadata(version))
+	}
+
+	return resp, nil
+}
+
+// fromV2Config converts the typed config into the JSON config document of v1
+// so that it goes through the same parsing and checks.
+func (s *gRPCServer) fromV2Config(config *pbv2.ServiceConfig) (*models.ServiceConfig, error) {
+	if config == nil {
+		return nil, invalidArgument("config", getConfigRequiredError())
+	}
+
+	data, err := formats.FlattenData(config.Data.AsMap())
+	if err != nil {
+		return nil, invalidArgument("config.data", err.Error())
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]map[string]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, map[string]string{k: data[k]})
+	}
+
+	var rules []models.Rule
+	for _, r := range config.Rules {
+		rules = append(rules, models.Rule{Key: r.Key, When: r.When, Value: r.Value})
+	}
+
+	var flags map[string]models.Flag
+	if len(config.Flags) != 0 {
+		flags = make(map[string]models.Flag, len(config.Flags))
+	}
+	for name, f := range config.Flags {
+		flag := models.Flag{Default: f.Default, Killed: f.Killed}
+		for _, r := range f.Rules {
+			flag.Rules = append(flag.Rules, models.FlagRule{When: r.When, Value: r.Value})
+		}
+		if f.Rollout != nil {
+			flag.Rollout = &models.Rollout{Percentage: f.Rollout.Percentage, Value: f.Rollout.Value}
+		}
+		flags[name] = flag
+	}
+
+	expires := make(map[string]time.Time, len(config.Expires))
+	for k, at := range config.Expires {
+		expires[k] = at.AsTime()
+	}
+
+	document, err := json.Marshal(map[string]interface{}{
+		"service": config.ServiceName,
+		"data":    pairs,
+		"parent":  config.Parent,
+		"merge":   config.Merge,
+		"secrets": config.Secrets,
+		"rules":   rules,
+		"flags":   flags,
+		"expires": expires,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.checkPayload(string(document)); err != nil {
+		return nil, err
+	}
+
+	serviceConfig := &models.ServiceConfig{}
+	if err = json.Unmarshal(document, &serviceConfig); err != nil {
+		return nil, invalidArgument("config", err.Error())
+	}
+
+	if err = s.checkLimits(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	return serviceConfig, nil
+}
+
+// toV2Config renders the service config with opts into the typed config,
+// values are typed according to the latest schema of the service.
+func (s *gRPCServer) toV2Config(ctx context.Context, sc *models.ServiceConfig, opts renderOptions) (*pbv2.ServiceConfig, error) {
+	rc, err := s.render(ctx, sc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := database.FromContext(ctx).Schema().Latest(sc.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	secretKeys := map[string]bool{}
+	for _, k := range rc.secrets {
+		secretKeys[k] = true
+	}
+
+	values := make(map[string]interface{}, len(rc.data))
+	for k, value := range rc.data {
+		if schema != nil && !secretKeys[k] {
+			values[k] = models.TypedValue(schema.Keys[k].Type, value)
+		} else {
+			values[k] = value
+		}
+	}
+
+	data, err := structpb.NewStruct(values)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &pbv2.ServiceConfig{
+		ServiceName: sc.Service,
+		Data:        data,
+		Parent:      sc.Spec.Parent,
+		Merge:       sc.Spec.Merge,
+		Secrets:     sc.Spec.Secrets,
+		Provenance:  rc.provenance,
+	}
+
+	for _, r := range sc.Spec.Rules {
+		config.Rules = append(config.Rules, &pbv2.Rule{Key: r.Key, When: r.When, Value: r.Value})
+	}
+
+	if len(sc.Spec.Flags) != 0 {
+		config.Flags = make(map[string]*pbv2.Flag, len(sc.Spec.Flags))
+	}
+	for name, f := range sc.Spec.Flags {
+		flag := &pbv2.Flag{Default: f.Default, Killed: f.Killed}
+		for _, r := range f.Rules {
+			flag.Rules = append(flag.Rules, &pbv2.FlagRule{When: r.When, Value: r.Value})
+		}
+		if f.Rollout != nil {
+			flag.Rollout = &pbv2.Rollout{Percentage: f.Rollout.Percentage, Value: f.Rollout.Value}
+		}
+		config.Flags[name] = flag
+	}
+
+	if len(sc.Spec.Expires) != 0 {
+		config.Expires = make(map[string]*timestamppb.Timestamp, len(sc.Spec.Expires))
+	}
+	for k, e := range sc.Spec.Expires {
+		config.Expires[k] = timestamppb.New(e.At)
+	}
+
+	// the metadata of a version is read from the list of versions starting
+	// right after it
+	versions, err := database.FromContext(ctx).ServiceConfig().ListVersions(sc, sc.Version+1, 1)
+	if err != nil {
+		return nil, err
+	} else if len(versions) != 0 {
+		config.Metadata = toVersionMetadata(versions[0])
+	}
+
+	return config, nil
+}
+
+func toVersionMetadata(v *models.VersionInfo) *pbv2.VersionMetadata {
+	metadata := &pbv2.VersionMetadata{
+		Version:     v.Version,
+		Message:     v.Message,
+		RollbackOf:  v.RollbackOf,
+		ChangesetId: uint32(v.ChangesetID),
+		Size:        v.Size,
+		Checksum:    v.Checksum,
+	}
+	if !v.CreatedAt.IsZero() {
+		metadata.CreatedAt = timestamppb.New(v.CreatedAt)
+	}
+	return metadata
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+)
+
+func getRollbackVersionRequiredError() string {
+	return fmt.Sprintf("version to roll back to is required")
+}
+
+func (s *gRPCServer) ListVersions(ctx context.Context, req *pb.ListVersionsRequest) (*pb.ListVersionsResponse, error) {
+
+	beforeVersion, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := pageSize(req.PageSize)
+
+	// one more version than requested tells whether there is a next page
+	screp := database.FromContext(ctx).ServiceConfig()
+	versions, err := screp.ListVersions(&models.ServiceConfig{Service: req.ServiceName}, uint32(beforeVersion), limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListVersionsResponse{Resp: "Success"}
+	if len(versions) > limit {
+		versions = versions[:limit]
+		resp.NextPageToken = encodePageToken(int(versions[limit-1].Version))
+	}
+
+	for _, v := range versions {
+		resp.Versions = append(resp.Versions, &pb.VersionInfo{
+			Version:     v.Version,
+			Size:        v.Size,
+			Checksum:    v.Checksum,
+			CreatedAt:   formatTime(v.CreatedAt),
+			Message:     v.Message,
+			RollbackOf:  v.RollbackOf,
+			ChangesetId: uint32(v.ChangesetID),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *gRPCServer) Rollback(ctx context.Context, req *pb.RollbackRequest) (*pb.RollbackResponse, error) {
+
+	if req.ToVersion == 0 {
+		return nil, invalidArgument("toVersion", getRollbackVersionRequiredError())
+	}
+
+	screp := database.FromContext(ctx).ServiceConfig()
+	target, err := screp.Read(&models.ServiceConfig{
+		Service: req.ServiceName,
+		Version: req.ToVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.validateServiceConfig(ctx, target); err != nil {
+		return nil, err
+	}
+
+	var serviceConfig *models.ServiceConfig
+	err = s.withQuota(ctx, target, false, func(psql *database.PostgreSQL) error {
+		serviceConfig, err = psql.ServiceConfig().Rollback(&models.Rollback{
+			Service:   req.ServiceName,
+			ToVersion: req.ToVersion,
+			Message:   req.Message,
+			Tag:       req.Tag,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RollbackResponse{Resp: "Success", Version: serviceConfig.Version}, nil
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/wphylici/contest-cloud/internal/database"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"github.com/wphylici/contest-cloud/internal/transport/grpc/pb"
+	"github.com/wphylici/contest-cloud/internal/watch"
+	"time"
+)
+
+const (
+	watchEventSnapshot  = "snapshot"
+	watchEventVersion   = "version"
+	watchEventDeleted   = "deleted"
+	watchEventHeartbeat = "heartbeat"
+	watchEventResync    = "resync"
+	watchEventParent    = "parent"
+)
+
+// Watch sends the current config of the service, or the versions created
+// after fromVersion when a client resumes, then pushes every new version and
+// deletion. A change of a parent layer sends the config rendered again in a
+// parent event. A client that does not keep up receives a resync event
+// followed by a new snapshot.
+func (s *gRPCServer) Watch(req *pb.WatchRequest, stream pb.ConfigController_WatchServer) error {
+
+	// subscribe first so that no change is missed while the state is sent
+	sub := s.hub.Subscribe(req.ServiceName)
+	defer s.hub.Unsubscribe(sub)
+
+	if err := s.watchLayers(sub, req.ServiceName); err != nil {
+		return err
+	}
+
+	lastVersion, err := s.sendWatchState(stream, req.ServiceName, req.FromVersion)
+	if err != nil {
+		return err
+	}
+
+	heartbeat := time.NewTicker(s.watchHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-heartbeat.C:
+			if err = sendWatchEvent(stream, &pb.WatchEvent{Type: watchEventHeartbeat, ServiceName: req.ServiceName}); err != nil {
+				return err
+			}
+		case <-sub.Resync():
+			sub.Drain()
+			if err = sendWatchEvent(stream, &pb.WatchEvent{Type: watchEventResync, ServiceName: req.ServiceName}); err != nil {
+				return err
+			}
+			if err = s.watchLayers(sub, req.ServiceName); err != nil {
+				return err
+			}
+			if lastVersion, err = s.sendWatchState(stream, req.ServiceName, 0); err != nil {
+				return err
+			}
+		case change := <-sub.Events():
+			switch {
+			case change.Service != req.ServiceName:
+				if lastVersion != 0 {
+					err = s.sendWatchVersion(stream, watchEventParent, &models.ServiceConfig{Service: req.ServiceName, Version: lastVersion})
+				}
+			case change.Kind == models.ChangeDeleted:
+				if change.Version == 0 || change.Version == lastVersion {
+					lastVersion = 0
+				}
+				err = sendWatchEvent(stream, &pb.WatchEvent{Type: watchEventDeleted, ServiceName: change.Service, Version: change.Version})
+			case change.Version > lastVersion:
+				lastVersion = change.Version
+				err = s.sendWatchVersion(stream, watchEventVersion, &models.ServiceConfig{Service: change.Service, Version: change.Version})
+			}
+			if err != nil {
+				return err
+			}
+
+			// the parent of a layer may change with any of its versions
+			if err = s.watchLayers(sub, req.ServiceName); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watchLayers subscribes to the service and to the parent layers of its latest
+// config, as far as they exist.
+func (s *gRPCServer) watchLayers(sub *watch.Subscription, serviceName string) error {
+	var serviceNames []string
+	visited := map[string]bool{}
+
+	screp := database.Psql.ServiceConfig()
+	for name := serviceName; name != "" && !visited[name]; {
+		visited[name] = true
+		serviceNames = append(serviceNames, name)
+
+		sc, err := screp.Read(&models.ServiceConfig{Service: name})
+		var notFoundErr *database.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			break
+		} else if err != nil {
+			return err
+		}
+		name = sc.Spec.Parent
+	}
+
+	s.hub.Resubscribe(sub, serviceNames...)
+	return nil
+}
+
+// sendWatchState sends the latest config of the service as a snapshot or the
+// versions newer than fromVersion. It returns the latest version of the
+// service config.
+func (s *gRPCServer) sendWatchState(stream pb.ConfigController_WatchServer, serviceName string, fromVersion uint32) (uint32, error) {
+	screp := database.Psql.ServiceConfig()
+
+	var versions []*models.ServiceConfig
+	if fromVersion != 0 {
+		var err error
+		if versions, err = screp.ReadVersionsAfter(&models.ServiceConfig{Service: serviceName}, fromVersion); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(versions) == 0 {
+		latest, err := screp.Read(&models.ServiceConfig{Service: serviceName})
+		var notFoundErr *database.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return 0, nil
+		} else if err != nil {
+			return 0, err
+		}
+
+		if fromVersion == 0 {
+			return latest.Version, s.sendWatchVersion(stream, watchEventSnapshot, latest)
+		}
+		return latest.Version, nil
+	}
+
+	for _, sc := range versions {
+		if err := s.sendWatchVersion(stream, watchEventVersion, sc); err != nil {
+			return 0, err
+		}
+	}
+
+	return versions[len(versions)-1].Version, nil
+}
+
+func (s *gRPCServer) sendWatchVersion(stream pb.ConfigController_WatchServer, eventType string, sc *models.ServiceConfig) error {
+	if sc.Data == nil {
+		var err error
+		if sc, err = database.Psql.ServiceConfig().Read(sc); err != nil {
+			return err
+		}
+	}
+
+	rc, err := s.render(stream.Context(), sc, renderOptions{secrets: secretsMasked})
+	if err != nil {
+		return err
+	}
+
+	configData, err := json.Marshal(rc.data)
+	if err != nil {
+		return err
+	}
+
+	return sendWatchEvent(stream, &pb.WatchEvent{
+		Type:        eventType,
+		ServiceName: sc.Service,
+		Version:     sc.Version,
+		ConfData:    string(configData),
+	})
+}
+
+func sendWatchEvent(stream pb.ConfigController_WatchServer, event *pb.WatchEvent) error {
+	event.Time = time.Now().Format(time.RFC3339)
+	return stream.Send(event)
+}
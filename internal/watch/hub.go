@@ -0,0 +1,141 @@
+// Package watch dispatches the changes of service configs to the clients
+// watching them.
+package watch
+
+import (
+	"github.com/wphylici/contest-cloud/internal/models"
+	"sync"
+)
+
+// Subscription receives the changes of a set of services. Changes are
+// buffered, a subscriber that does not keep up loses them and is asked to
+// resync.
+type Subscription struct {
+	Services []string
+	events   chan *models.ConfigChange
+	resync   chan struct{}
+}
+
+func (s *Subscription) Events() <-chan *models.ConfigChange {
+	return s.events
+}
+
+// Resync is signaled when changes were lost and the subscriber has to reload
+// the state of the services.
+func (s *Subscription) Resync() <-chan struct{} {
+	return s.resync
+}
+
+// Drain discards the buffered changes.
+func (s *Subscription) Drain() {
+	for {
+		select {
+		case <-s.events:
+		default:
+			return
+		}
+	}
+}
+
+func (s *Subscription) requestResync() {
+	select {
+	case s.resync <- struct{}{}:
+	default:
+	}
+}
+
+type Hub struct {
+	mu            sync.Mutex
+	bufferSize    int
+	subscriptions map[string]map[*Subscription]bool
+}
+
+func NewHub(bufferSize int) *Hub {
+	return &Hub{
+		bufferSize:    bufferSize,
+		subscriptions: map[string]map[*Subscription]bool{},
+	}
+}
+
+func (h *Hub) Subscribe(serviceNames ...string) *Subscription {
+	sub := &Subscription{
+		events: make(chan *models.ConfigChange, h.bufferSize),
+		resync: make(chan struct{}, 1),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.add(sub, serviceNames)
+
+	return sub
+}
+
+// Resubscribe replaces the services the subscription receives the changes of.
+func (h *Hub) Resubscribe(sub *Subscription, serviceNames ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub)
+	h.add(sub, serviceNames)
+}
+
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub)
+}
+
+func (h *Hub) add(sub *Subscription, serviceNames []string) {
+	sub.Services = nil
+	for _, serviceName := range serviceNames {
+		if h.subscriptions[serviceName] == nil {
+			h.subscriptions[serviceName] = map[*Subscription]bool{}
+		} else if h.subscriptions[serviceName][sub] {
+			continue
+		}
+		h.subscriptions[serviceName][sub] = true
+		sub.Services = append(sub.Services, serviceName)
+	}
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	for _, serviceName := range sub.Services {
+		delete(h.subscriptions[serviceName], sub)
+		if len(h.subscriptions[serviceName]) == 0 {
+			delete(h.subscriptions, serviceName)
+		}
+	}
+	sub.Services = nil
+}
+
+// Run dispatches the changes until the channel is closed. A nil change asks
+// every subscriber to resync.
+func (h *Hub) Run(changes <-chan *models.ConfigChange) {
+	for change := range changes {
+		h.Publish(change)
+	}
+}
+
+func (h *Hub) Publish(change *models.ConfigChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if change == nil {
+		for _, subs := range h.subscriptions {
+			for sub := range subs {
+				sub.requestResync()
+			}
+		}
+		return
+	}
+
+	for sub := range h.subscriptions[change.Service] {
+		select {
+		case sub.events <- change:
+		default:
+			sub.requestResync()
+		}
+	}
+}
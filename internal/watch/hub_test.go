@@ -0,0 +1,63 @@
+package watch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wphylici/contest-cloud/internal/models"
+	"testing"
+)
+
+func TestHubPublish(t *testing.T) {
+	h := NewHub(2)
+
+	billing := h.Subscribe("billing")
+	search := h.Subscribe("search")
+
+	change := &models.ConfigChange{Service: "billing", Version: 2, Kind: models.ChangeVersion}
+	h.Publish(change)
+
+	assert.Equal(t, change, <-billing.Events())
+	assert.Len(t, search.Events(), 0)
+
+	h.Unsubscribe(billing)
+	h.Publish(change)
+	assert.Len(t, billing.Events(), 0)
+}
+
+func TestHubResubscribe(t *testing.T) {
+	h := NewHub(2)
+	sub := h.Subscribe("billing", "base")
+
+	h.Publish(&models.ConfigChange{Service: "base", Version: 4, Kind: models.ChangeVersion})
+	assert.Len(t, sub.Events(), 1)
+	sub.Drain()
+
+	h.Resubscribe(sub, "billing", "payments-base")
+	assert.Equal(t, []string{"billing", "payments-base"}, sub.Services)
+
+	h.Publish(&models.ConfigChange{Service: "base", Version: 5, Kind: models.ChangeVersion})
+	assert.Len(t, sub.Events(), 0)
+	h.Publish(&models.ConfigChange{Service: "payments-base", Version: 1, Kind: models.ChangeVersion})
+	assert.Len(t, sub.Events(), 1)
+
+	h.Unsubscribe(sub)
+	assert.Empty(t, h.subscriptions)
+}
+
+func TestHubResync(t *testing.T) {
+	h := NewHub(2)
+	sub := h.Subscribe("billing")
+
+	for version := uint32(1); version <= 3; version++ {
+		h.Publish(&models.ConfigChange{Service: "billing", Version: version, Kind: models.ChangeVersion})
+	}
+
+	assert.Len(t, sub.Events(), 2)
+	assert.Len(t, sub.Resync(), 1)
+
+	sub.Drain()
+	<-sub.Resync()
+	assert.Len(t, sub.Events(), 0)
+
+	h.Publish(nil)
+	assert.Len(t, sub.Resync(), 1)
+}